@@ -6,9 +6,11 @@ import (
 	"fmt"
 	"go/ast"
 	"go/constant"
+	"go/token"
 	"go/types"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
@@ -40,8 +42,16 @@ type Analyser struct {
 	// get the structs which are member of an interface
 	interfaces map[*types.Interface][]*types.Named
 
-	// map type string to data storage
-	constructors map[string]*types.Basic
+	// map type string to custom constructors
+	constructors map[string]constructor
+
+	// map qualified type names to registered codecs
+	codecs map[string]Codec
+
+	// wrapper structs being analysed, which are not members
+	// of the union of their inner value, so that wrappers
+	// may not be nested
+	unwrapping map[*types.Named]bool
 
 	// StandaloneUnions returns the union with an implicit union tag scheme,
 	// for which standalone parsing/writing function should be generated
@@ -50,6 +60,11 @@ type Analyser struct {
 	// ChildTypes contains types that are used in other types.
 	// For instance, top-level tables have a [false] value.
 	ChildTypes map[*types.Named]bool
+
+	// Diagnostics are the (non fatal) issues found
+	// during the analysis, such as invalid constructors,
+	// prefixed by their position.
+	Diagnostics []string
 }
 
 // ImportSource loads the source go file with go/packages,
@@ -88,11 +103,13 @@ func NewAnalyserFromPkg(pkg *packages.Package, sourcePath, sourceAbsPath string)
 	an.fetchUnionFlags()
 	an.fetchInterfaces()
 	an.fetchConstructors()
+	an.fetchCodecs()
 
 	// perform the actual analysis
 	an.Tables = make(map[*types.Named]Struct)
 	an.StandaloneUnions = make(map[*types.Named]Union)
 	an.ChildTypes = make(map[*types.Named]bool)
+	an.unwrapping = make(map[*types.Named]bool)
 	for _, ty := range an.fetchSource() {
 		an.handleTable(ty, false)
 	}
@@ -127,6 +144,9 @@ func getSyntaxFields(scope *types.Scope, ty *ast.TypeSpec, st *ast.StructType) (
 
 func (an *Analyser) PackageName() string { return an.pkg.Name }
 
+// Package returns the package of the analysed source file
+func (an *Analyser) Package() *types.Package { return an.pkg.Types }
+
 // ByName returns the type with name [name], or panic
 // if it does not exist
 func (an *Analyser) ByName(name string) *types.Named {
@@ -267,33 +287,6 @@ func (an *Analyser) fetchInterfaces() {
 	}
 }
 
-func (an *Analyser) fetchConstructors() {
-	an.constructors = make(map[string]*types.Basic)
-
-	scope := an.pkg.Types.Scope()
-	names := scope.Names()
-
-	for _, name := range names {
-		obj := scope.Lookup(name)
-
-		fn, isFunction := obj.(*types.Func)
-		if !isFunction {
-			continue
-		}
-
-		sig := fn.Type().(*types.Signature)
-
-		// look for <...>FromUint and patterns
-		if typeName, _, ok := strings.Cut(fn.Name(), "FromUint"); ok {
-			if sig.Params().Len() != 1 {
-				panic("invalid signature for constructor " + fn.Name())
-			}
-			arg := sig.Params().At(0).Type().(*types.Basic)
-			an.constructors[typeName] = arg
-		}
-	}
-}
-
 // handle table wraps [createFromStruct] by registering
 // the type in [Tables]
 func (an *Analyser) handleTable(ty *types.Named, isChildType bool) Struct {
@@ -360,7 +353,8 @@ func (an *Analyser) createTypeFor(ty types.Type, tags parsedTags, decl ast.Expr)
 		target := an.createTypeFor(ty, tags, decl)
 		_, isFixedSize := target.IsFixedSize()
 		_, isStruct := target.(Struct)
-		if isFixedSize && !isStruct {
+		_, isCodec := target.(Codec)
+		if isFixedSize && !isStruct && !isCodec {
 			panic("offset to (non struct) fixed size type is not supported")
 		}
 		if isPointer && !isStruct {
@@ -369,16 +363,53 @@ func (an *Analyser) createTypeFor(ty types.Type, tags parsedTags, decl ast.Expr)
 		return Offset{Target: target, Size: offset.binary(), IsPointer: isPointer}
 	}
 
+	if codec, hasCodec := an.codecs[typeKey(ty)]; hasCodec {
+		return codec
+	}
+
+	if compression := tags.compression; compression != NoCompression {
+		// adjust the tags and "recurse" to the actual type
+		tags.compression = NoCompression
+		target := an.createTypeFor(ty, tags, decl)
+		_, isStruct := target.(Struct)
+		slice, isSlice := target.(Slice)
+		if !isStruct && !(isSlice && slice.IsRawData()) {
+			panic("compression is only supported for structs and raw data")
+		}
+		if isSlice && slice.Count != NoLength {
+			panic("the length of compressed raw data is given by the uncompressedLength tag")
+		}
+		return Compressed{Target: target, Algorithm: compression, UncompressedLength: tags.uncompressedLength}
+	}
+
 	// now inspect the actual go type
 	switch under := ty.Underlying().(type) {
 	case *types.Basic:
-		return an.createFromBasic(ty, decl)
+		if under.Info()&types.IsString != 0 {
+			return newString(ty, tags)
+		}
+		if encoding, isVarInt := newVarIntEncoding(tags.encoding); isVarInt {
+			if tags.binarySize.size != 0 || tags.isEnum {
+				panic("binarySize and isEnum are not supported for variable-length encodings")
+			}
+			return newVarInt(ty, encoding)
+		}
+		return an.createFromBasic(ty, decl, tags)
 	case *types.Array:
 		elemDecl := sliceElement(decl)
 		// handle array of offsets by adujsting [offsetSize]
-		elemTags := parsedTags{offsetSize: tags.offsetsArray}
+		elemTags := parsedTags{offsetSize: tags.offsetsArray, encoding: tags.encoding, binarySize: tags.binarySize, isEnum: tags.isEnum}
 		// recurse on the element
 		elem := an.createTypeFor(under.Elem(), elemTags, elemDecl)
+		checkBinarySizeElement(elem, tags.binarySize)
+		if _, isVarInt := elem.(VarInt); isVarInt {
+			panic(fmt.Sprintf("variable-length encodings are not supported for arrays (%s)", ty))
+		}
+		if _, isFixedSize := elem.IsFixedSize(); !isFixedSize {
+			if _, isStruct := elem.(Struct); !isStruct {
+				panic(fmt.Sprintf("arrays are only supported for elements with static size or structs (%s)", ty))
+			}
+		}
 		return Array{origin: ty, Len: int(under.Len()), Elem: elem}
 	case *types.Struct:
 		// anonymous structs are not supported
@@ -386,14 +417,38 @@ func (an *Analyser) createTypeFor(ty types.Type, tags parsedTags, decl ast.Expr)
 	case *types.Slice:
 		elemDecl := sliceElement(decl)
 		// handle array of offsets by adujsting [offsetSize]
-		elemTags := parsedTags{offsetSize: tags.offsetsArray}
+		elemTags := parsedTags{offsetSize: tags.offsetsArray, encoding: tags.encoding, binarySize: tags.binarySize, isEnum: tags.isEnum}
 		// recurse on the element
 		elem := an.createTypeFor(under.Elem(), elemTags, elemDecl)
-		return Slice{
+		checkBinarySizeElement(elem, tags.binarySize)
+		if ba, isBasic := elem.(Basic); isBasic && len(ba.Enum) != 0 && !(tags.bitWidth.IsEmpty() && tags.byteWidth.IsEmpty()) {
+			panic(fmt.Sprintf("isEnum is not supported with bitWidth and byteWidth (%s)", ty))
+		}
+		if !tags.bitWidth.IsEmpty() {
+			if basic, isBasic := elem.Origin().Underlying().(*types.Basic); !isBasic || basic.Info()&types.IsUnsigned == 0 {
+				panic(fmt.Sprintf("bitWidth is only supported for slices of unsigned integers, got %s", ty))
+			}
+		}
+		if !tags.byteWidth.IsEmpty() {
+			_, isOffset := elem.(Offset)
+			basic, isBasic := elem.(Basic)
+			if !isOffset && !(isBasic && basic.origin.Underlying().(*types.Basic).Info()&types.IsUnsigned != 0) {
+				panic(fmt.Sprintf("byteWidth is only supported for slices of unsigned integers or offsets, got %s", ty))
+			}
+		}
+		out := Slice{
 			origin: ty, Elem: elem,
 			Count: tags.arrayCount, CountExpr: tags.arrayCountField,
 			SubsliceStart: tags.subsliceStart,
+			BitWidth:      tags.bitWidth,
+			ByteWidth:     tags.byteWidth,
+		}
+		if tags.sortedBy != "" {
+			out.SortedBy = newSortKey(elem, tags.sortedBy, tags.checkSorted)
 		}
+		return out
+	case *types.Map:
+		return an.createMap(ty, under, tags)
 	case *types.Interface:
 		// anonymous interface are not supported
 		return an.createFromInterface(ty.(*types.Named), tags.unionField)
@@ -402,16 +457,258 @@ func (an *Analyser) createTypeFor(ty types.Type, tags parsedTags, decl ast.Expr)
 	}
 }
 
+// newString uses [tags] to resolve how the bytes of the string are delimited
+func newString(ty types.Type, tags parsedTags) String {
+	encoding, ok := newStringEncoding(tags.encoding)
+	if !ok {
+		panic("invalid encoding for string: " + tags.encoding)
+	}
+	out := String{origin: ty, Encoding: encoding}
+	out.Data = Slice{
+		origin: types.NewSlice(types.Typ[types.Byte]), Elem: Basic{origin: types.Typ[types.Byte]},
+		Count: tags.arrayCount, CountExpr: tags.arrayCountField,
+		SubsliceStart: tags.subsliceStart,
+	}
+	switch layout := tags.stringLayout; {
+	case layout == "":
+	case layout == "Pascal":
+		if tags.arrayCount != NoLength {
+			panic("arrayCount is not supported for Pascal strings")
+		}
+		out.Data.Count = FirstUint8
+	case strings.HasPrefix(layout, "Fixed-"):
+		if tags.arrayCount != NoLength {
+			panic("arrayCount is not supported for fixed length strings")
+		}
+		out.FixedLength = parsePositiveSize("stringLayout", strings.TrimPrefix(layout, "Fixed-"))
+		out.Data = Slice{}
+	default:
+		panic("invalid tag for stringLayout: " + layout)
+	}
+	if out.Encoding == UTF16BE && out.FixedLength%2 != 0 {
+		panic("the length of UTF-16BE strings must be even")
+	}
+	return out
+}
+
+// createMap resolves the array of records storing the values of [ty],
+// whose key field is given by the mapKey tag
+func (an *Analyser) createMap(ty types.Type, under *types.Map, tags parsedTags) Map {
+	if tags.mapKey == "" {
+		panic(fmt.Sprintf("map type %s requires a mapKey tag", ty))
+	}
+	key := tags.mapKey
+	tags.mapKey = ""
+	records := an.createTypeFor(types.NewSlice(under.Elem()), tags, nil).(Slice)
+
+	elem := records.Elem
+	if of, isOffset := elem.(Offset); isOffset { // records stored at offsets
+		elem = of.Target
+	}
+	st, isStruct := elem.(Struct)
+	if !isStruct {
+		panic(fmt.Sprintf("map values must be structs, got %s", under.Elem()))
+	}
+	goStruct := st.origin.Underlying().(*types.Struct)
+	for i := 0; i < goStruct.NumFields(); i++ {
+		if field := goStruct.Field(i); field.Name() == key {
+			if !types.Identical(field.Type(), under.Key()) {
+				panic(fmt.Sprintf("map key %s has type %s, expected %s", key, field.Type(), under.Key()))
+			}
+			return Map{origin: ty, Records: records, Key: key}
+		}
+	}
+	panic(fmt.Sprintf("unknown map key %s in %s", key, st.origin))
+}
+
+// newSortKey resolves the key fields of [elem],
+// which must be a fixed size struct
+func newSortKey(elem Type, tag string, check bool) SortKey {
+	st, isStruct := elem.(Struct)
+	if _, isFixedSize := elem.IsFixedSize(); !isStruct || !isFixedSize {
+		panic(fmt.Sprintf("sortedBy is only supported for slices of fixed size structs, got %s", elem.Origin()))
+	}
+	start, end, _ := strings.Cut(tag, ",")
+	out := SortKey{Start: strings.TrimSpace(start), End: strings.TrimSpace(end), Check: check}
+
+	keyType := func(name string) types.Type {
+		for _, field := range st.Fields {
+			if field.Name != name {
+				continue
+			}
+			if basic, isBasic := field.Type.(Basic); isBasic && basic.origin.Underlying().(*types.Basic).Info()&types.IsInteger != 0 {
+				return basic.origin
+			}
+			panic(fmt.Sprintf("sort key %s must be an integer", name))
+		}
+		panic(fmt.Sprintf("unknown sort key %s in %s", name, st.origin))
+	}
+	out.origin = keyType(out.Start)
+	if out.IsRange() && !types.Identical(out.origin, keyType(out.End)) {
+		panic(fmt.Sprintf("sort keys %s and %s must have the same type", out.Start, out.End))
+	}
+	return out
+}
+
+// [ty] has underlying type Basic, and must be able
+// to store the values of [encoding]
+func newVarInt(ty types.Type, encoding VarIntEncoding) VarInt {
+	kind := ty.Underlying().(*types.Basic).Kind()
+	var ok bool
+	switch encoding {
+	case UIntBase128:
+		ok = kind == types.Uint32 // larger values are invalid, and are not decoded
+	case U255UInt16:
+		ok = kind == types.Uint16 || kind == types.Uint32 || kind == types.Uint64 || kind == types.Int32 || kind == types.Int64
+	case CFFOperand:
+		ok = kind == types.Int32 || kind == types.Int64 || kind == types.Float32 || kind == types.Float64
+	}
+	if !ok {
+		panic(fmt.Sprintf("type %s can't store %s values", ty, encoding))
+	}
+	return VarInt{origin: ty, Encoding: encoding}
+}
+
+// newEnum returns the integer type [ty] with its
+// declared constants
+func (an *Analyser) newEnum(ty types.Type) Basic {
+	named, isNamed := ty.(*types.Named)
+	if !isNamed || named.Underlying().(*types.Basic).Info()&types.IsInteger == 0 {
+		panic(fmt.Sprintf("isEnum is only supported for named integer types, got %s", ty))
+	}
+	out := Basic{origin: ty}
+	scope := an.pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		if cst, isConst := scope.Lookup(name).(*types.Const); isConst && types.Identical(cst.Type(), named) {
+			out.Enum = append(out.Enum, cst)
+		}
+	}
+	if len(out.Enum) == 0 {
+		panic(fmt.Sprintf("enum %s does not have any constant", ty))
+	}
+	sort.SliceStable(out.Enum, func(i, j int) bool {
+		return constant.Compare(out.Enum[i].Val(), token.LSS, out.Enum[j].Val())
+	})
+	return out
+}
+
+// [ty] has underlying type float32 or float64
+func newFloat(ty types.Type, encoding string) Basic {
+	enc, ok := newFloatEncoding(encoding)
+	if !ok {
+		panic("invalid encoding for float: " + encoding)
+	}
+	out := Basic{origin: ty, FloatEncoding: enc}
+	switch enc {
+	case F2Dot14:
+		out.Size = Uint16
+	case Fixed:
+		out.Size = Uint32
+	}
+	return out
+}
+
+// ScalarsPackage is the import path of the package defining
+// the Opentype scalar types, which are supported
+// without custom constructors
+const ScalarsPackage = "github.com/benoitkugler/binarygen/opentype"
+
+// scalarSizes are the types of [ScalarsPackage] whose binary
+// size differs from the size of their Go type
+var scalarSizes = map[string]BinarySize{
+	"Offset24": Uint24,
+}
+
+// IsScalar returns true for the types defined in [ScalarsPackage]
+func IsScalar(ty types.Type) bool {
+	named, isNamed := ty.(*types.Named)
+	return isNamed && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == ScalarsPackage
+}
+
 // [ty] has underlying type Basic
-func (an *Analyser) createFromBasic(ty types.Type, decl ast.Expr) Type {
+func (an *Analyser) createFromBasic(ty types.Type, decl ast.Expr, tags parsedTags) Type {
+	binarySize := tags.binarySize
+	if IsScalar(ty) {
+		if binarySize.size != 0 || tags.encoding != "" || tags.isEnum {
+			panic(fmt.Sprintf("binarySize, encoding and isEnum are not supported for %s", ty))
+		}
+		obj, _, _ := types.LookupFieldOrMethod(ty, false, nil, "Validate")
+		return Basic{origin: ty, Size: scalarSizes[ty.(*types.Named).Obj().Name()], HasValidate: obj != nil}
+	}
 	// check for custom constructors
 	name := an.resolveName(ty, decl)
-	if binaryType, hasConstructor := an.constructors[name]; hasConstructor {
-		size, _ := newBinarySize(binaryType)
-		return DerivedFromBasic{origin: ty, Name: name, Size: size}
+	if cons, hasConstructor := an.constructors[name]; hasConstructor {
+		if binarySize.size != 0 || tags.encoding != "" {
+			panic(fmt.Sprintf("binarySize and encoding are not supported for types with custom constructors (%s)", name))
+		}
+		size, _ := newBinarySize(cons.storage)
+		return DerivedFromBasic{origin: ty, Name: name, Size: size, IsMethod: cons.isMethod, IsFallible: cons.isFallible}
+	}
+
+	basic := ty.Underlying().(*types.Basic)
+	if tags.isEnum {
+		if binarySize.size != 0 {
+			panic(fmt.Sprintf("binarySize is not supported for enums (%s)", ty))
+		}
+		return an.newEnum(ty)
+	}
+	if basic.Info()&types.IsFloat != 0 {
+		if binarySize.size != 0 {
+			panic("binarySize is not supported for floats: use the encoding tag")
+		}
+		return newFloat(ty, tags.encoding)
+	}
+	if binarySize.size == 0 {
+		if _, ok := newBinarySize(basic); !ok && basic.Info()&types.IsInteger != 0 {
+			panic(fmt.Sprintf("type %s has no fixed binary size: use the binarySize tag", ty))
+		}
+		return Basic{origin: ty}
+	}
+
+	if basic.Info()&types.IsInteger == 0 {
+		panic(fmt.Sprintf("binarySize is only supported for integers, got %s", ty))
+	}
+	// the Go type must be able to store the values
+	goSize, ok := newBinarySize(basic)
+	if !ok { // int, uint and uintptr are at most 64 bits
+		goSize = Uint64
+	}
+	goSigned := basic.Info()&types.IsUnsigned == 0
+	if binarySize.signed && !goSigned {
+		panic(fmt.Sprintf("type %s can't store signed values", ty))
+	}
+	if goSize < binarySize.size || (goSigned && !binarySize.signed && goSize == binarySize.size) {
+		panic(fmt.Sprintf("type %s can't store the values of binarySize %d", ty, binarySize.size))
+	}
+	return Basic{origin: ty, Size: binarySize.size, Signed: binarySize.signed}
+}
+
+// withStrictBools marks the bools of [ty], or the bool
+// elements of arrays and slices, as strict
+func withStrictBools(ty Type) Type {
+	switch ty := ty.(type) {
+	case Basic:
+		ty.Strict = ty.IsBool()
+		return ty
+	case Array:
+		ty.Elem = withStrictBools(ty.Elem)
+		return ty
+	case Slice:
+		ty.Elem = withStrictBools(ty.Elem)
+		return ty
 	}
+	return ty
+}
 
-	return Basic{origin: ty}
+// checkBinarySizeElement panics if [binarySize] is used
+// for an array whose elements are not integers
+func checkBinarySizeElement(elem Type, binarySize binaryInt) {
+	if binarySize.size == 0 {
+		return
+	}
+	if _, isBasic := elem.(Basic); !isBasic {
+		panic(fmt.Sprintf("binarySize is only supported for (arrays of) integers, got %s", elem.Origin()))
+	}
 }
 
 func (an *Analyser) createFromStruct(ty *types.Named) Struct {
@@ -419,48 +716,307 @@ func (an *Analyser) createFromStruct(ty *types.Named) Struct {
 	cm := an.commentsMap[ty]
 	out := Struct{
 		origin:    ty,
-		Fields:    make([]Field, st.NumFields()),
+		Fields:    make([]Field, 0, st.NumFields()),
 		Arguments: cm.externalArguments,
 	}
 
+	if cm.unwrap {
+		an.unwrapping[ty] = true
+		defer delete(an.unwrapping, ty)
+	}
+
+	// fields excluded with binary:"-" and flags set when unwrapping
+	// union members are not stored in binary files : they are not analysed
+	skipped := map[string]bool{}
+	for i := 0; i < st.NumFields(); i++ {
+		tags := reflect.StructTag(st.Tag(i))
+		if binary, ok := tags.Lookup("binary"); ok {
+			if binary != "-" {
+				panic(fmt.Sprintf("invalid tag for binary: %s (%s)", binary, st.Field(i)))
+			}
+			skipped[st.Field(i).Name()] = true
+		}
+		if flag := tags.Get("unwrapFlag"); flag != "" {
+			skipped[flag] = true
+		}
+	}
+
 	customParseFunc := map[string]bool{}
+	afterHooks := map[string]*types.Func{} // by field
 	for i := 0; i < ty.NumMethods(); i++ {
 		m := ty.Method(i)
+		if an.isGenerated(m) { // methods are generated for validation and enums
+			continue
+		}
 		mName := m.Name()
 		if mName == "parseEnd" {
 			out.ParseEnd = m
+		} else if mName == "parseStart" {
+			hook := an.newHook(m, []types.Type{types.NewSlice(types.Typ[types.Byte])}, cm.externalArguments)
+			out.ParseStart = &hook
+		} else if mName == "validate" {
+			hook := an.newHook(m, nil, cm.externalArguments)
+			out.Validate = &hook
+		} else if field, ok := hookField(mName); ok {
+			afterHooks[field] = m
 		} else if _, field, ok := strings.Cut(mName, "parse"); ok {
 			returnsLength := m.Type().(*types.Signature).Results().Len() == 2
 			customParseFunc[field] = returnsLength
 		}
 	}
 
-	for i := range out.Fields {
+	for i := 0; i < st.NumFields(); i++ {
 		field := st.Field(i)
+		if skipped[field.Name()] {
+			continue
+		}
 
 		// process the struct tags
 		tags := newTags(st, reflect.StructTag(st.Tag(i)))
 
+		if tags.align > 1 {
+			out.Fields = appendPadding(out.Fields, tags.align, cm.strict)
+		}
+
+		if tags.isEnum && tags.bits != nil {
+			panic(fmt.Sprintf("isEnum is not supported for bitfields (%s)", field))
+		}
+		if len(tags.expect) != 0 && (tags.bits != nil || field.Name() == "_" || tags.reserved != 0) {
+			panic(fmt.Sprintf("expect tag is not supported for bitfields and reserved fields (%s)", field))
+		}
+		if !tags.constraints.IsEmpty() && (tags.bits != nil || field.Name() == "_" || tags.reserved != 0) {
+			panic(fmt.Sprintf("constraints are not supported for bitfields and reserved fields (%s)", field))
+		}
+		if !tags.presentIf.IsEmpty() && (tags.bits != nil || field.Name() == "_" || tags.reserved != 0) {
+			panic(fmt.Sprintf("presentIf tag is not supported for bitfields and reserved fields (%s)", field))
+		}
+
+		if tags.bits != nil {
+			out.Fields = appendBitfield(out.Fields, field, *tags.bits)
+			continue
+		}
+
+		if field.Name() == "_" || tags.reserved != 0 {
+			out.Fields = append(out.Fields, an.createReserved(field, tags, cm.strict))
+			continue
+		}
+
 		astDecl := an.forAliases[ty][field.Name()]
 
 		fieldType := an.createTypeFor(field.Type(), tags, astDecl)
+		if cm.strict {
+			fieldType = withStrictBools(fieldType)
+		}
 		if opaque, isOpaque := fieldType.(Opaque); isOpaque {
 			opaque.ParserReturnsLength = customParseFunc[strings.Title(field.Name())]
 			fieldType = opaque
 		}
 
-		out.Fields[i] = Field{
+		out.Fields = append(out.Fields, Field{
 			Name:                      field.Name(),
 			Type:                      fieldType,
 			ArgumentsProvidedByFields: tags.requiredFieldArguments,
 			UnionTag:                  tags.unionTag,
 			OffsetRelativeTo:          tags.offsetRelativeTo,
+			Checksum:                  Checksum{Algorithm: tags.checksum, Start: tags.checksumStart, Length: tags.checksumLength},
+			Expect:                    tags.expect,
+			Constraints:               tags.constraints,
+			PresentIf:                 tags.presentIf,
+			UnwrapFlag:                tags.unwrapFlag,
+		})
+		if m := afterHooks[strings.Title(field.Name())]; m != nil {
+			hook := an.newHook(m, nil, cm.externalArguments)
+			out.Fields[len(out.Fields)-1].After = &hook
+			delete(afterHooks, strings.Title(field.Name()))
+		}
+		if tags.unwrapFlag != "" && !hasWrapper(fieldType) {
+			panic(fmt.Sprintf("unwrapFlag tag requires a union with a wrapper member (%s)", field))
+		}
+		if len(tags.expect) != 0 {
+			checkRepresentable(field, "expect", fieldType, tags.expect)
+		}
+		if cs := tags.constraints; !cs.IsEmpty() {
+			checkConstraints(field, fieldType, cs)
+		}
+		if !tags.presentIf.IsEmpty() {
+			checkOptional(field, fieldType, tags)
+		}
+	}
+
+	out.resolveChecksums()
+
+	if cm.unwrap {
+		out.Unwrap = innerField(out)
+	}
+
+	for _, m := range afterHooks {
+		panic(fmt.Sprintf("hook %s does not match any field", m))
+	}
+	if out.Validate != nil && out.HasConstraints() {
+		panic(fmt.Sprintf("validate method of %s conflicts with the one generated for constraints", ty.Obj().Name()))
+	}
+
+	for _, field := range out.Fields {
+		if bf, isBitfield := field.Type.(Bitfield); isBitfield {
+			if err := bf.validate(); err != nil {
+				panic(fmt.Sprintf("invalid bitfield in %s: %s", ty.Obj().Name(), err))
+			}
 		}
 	}
 
 	return out
 }
 
+// checkOptional checks that the field may be omitted:
+// only static sized fields and offsets are supported
+func checkOptional(field *types.Var, ty Type, tags parsedTags) {
+	if !tags.constraints.IsEmpty() {
+		panic(fmt.Sprintf("constraints are not supported for optional fields (%s)", field))
+	}
+	if _, isOffset := ty.(Offset); isOffset {
+		return
+	}
+	if _, isFixedSize := ty.IsFixedSize(); !isFixedSize || hasCountPrefix(ty) {
+		panic(fmt.Sprintf("presentIf tag is only supported for fixed size fields and offsets (%s)", field))
+	}
+}
+
+// checkConstraints checks that [cs] may be applied to [ty],
+// which must be an integer or a slice or array of integers
+func checkConstraints(field *types.Var, ty Type, cs Constraints) {
+	switch elem := ty.(type) {
+	case Slice:
+		ty = elem.Elem
+	case Array:
+		ty = elem.Elem
+	}
+	var values []constant.Value
+	if cs.Min != nil {
+		values = append(values, cs.Min)
+	}
+	if cs.Max != nil {
+		values = append(values, cs.Max)
+	}
+	checkRepresentable(field, "constraint", ty, append(values, cs.OneOf...))
+}
+
+// checkRepresentable checks that [values] may be stored in [ty],
+// which must be an integer
+func checkRepresentable(field *types.Var, tagName string, ty Type, values []constant.Value) {
+	basic, isBasic := ty.(Basic)
+	if !isBasic || basic.origin.Underlying().(*types.Basic).Info()&types.IsInteger == 0 {
+		panic(fmt.Sprintf("%s tag is only supported for integers (%s)", tagName, field))
+	}
+	size, _ := basic.IsFixedSize()
+	bits := uint(8 * size)
+	// bounds are [min, max)
+	min, max := constant.MakeInt64(0), constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+	if basic.origin.Underlying().(*types.Basic).Info()&types.IsUnsigned == 0 {
+		max = constant.Shift(constant.MakeInt64(1), token.SHL, bits-1)
+		min = constant.UnaryOp(token.SUB, max, 0)
+	}
+	for _, value := range values {
+		if constant.Compare(value, token.LSS, min) || constant.Compare(value, token.GEQ, max) {
+			panic(fmt.Sprintf("%s value %s overflows %s", tagName, value, field))
+		}
+	}
+}
+
+// createReserved returns a field skipping the bytes of [field],
+// whose size is given by the tags or by its type
+func (an *Analyser) createReserved(field *types.Var, tags parsedTags, strict bool) Field {
+	size := tags.reserved
+	if size == 0 {
+		var isFixedSize bool
+		size, isFixedSize = an.createTypeFor(field.Type(), tags, nil).IsFixedSize()
+		if !isFixedSize {
+			panic(fmt.Sprintf("reserved field %s must have a fixed size", field))
+		}
+	}
+	return Field{Name: field.Name(), Type: Reserved{origin: field.Type(), Size: size, Strict: strict}}
+}
+
+// appendPadding adds the field required to align
+// the next field on [align] bytes, if any.
+// When the offset is known at compile time, the padding
+// is a [Reserved] field, so that it is part of a fixed size scope.
+func appendPadding(fields []Field, align BinarySize, strict bool) []Field {
+	offset, isStatic := staticOffset(fields)
+	if !isStatic {
+		return append(fields, Field{Name: "_", Type: Padding{Align: align, Strict: strict}})
+	}
+	padding := (align - offset%align) % align
+	if padding == 0 {
+		return fields
+	}
+	origin := types.NewArray(types.Typ[types.Byte], int64(padding))
+	return append(fields, Field{Name: "_", Type: Reserved{origin: origin, Size: padding, Strict: strict}})
+}
+
+// staticOffset returns the offset following [fields],
+// if it is known at compile time
+func staticOffset(fields []Field) (BinarySize, bool) {
+	var offset BinarySize
+	for _, field := range fields {
+		size, isFixedSize := field.Type.IsFixedSize()
+		if _, isOffset := field.Type.(Offset); !isFixedSize && !isOffset {
+			return 0, false
+		}
+		offset += size
+	}
+	return offset, true
+}
+
+// resolveChecksums checks the type of the checksum fields,
+// and their offset when required
+func (st Struct) resolveChecksums() {
+	offset, isStatic := BinarySize(0), true
+	for i, field := range st.Fields {
+		if field.Checksum.Algorithm != NoChecksum {
+			basic, isBasic := field.Type.(Basic)
+			if size, _ := field.Type.IsFixedSize(); !isBasic || size != Uint32 || basic.origin.Underlying().(*types.Basic).Info()&types.IsUnsigned == 0 {
+				panic(fmt.Sprintf("checksum field %s must be an uint32", field.Name))
+			}
+			if field.Checksum.IsTable() {
+				if !isStatic {
+					panic(fmt.Sprintf("checksum field %s for the whole table must have a static offset", field.Name))
+				}
+				st.Fields[i].Checksum.Offset = offset
+			}
+		}
+
+		size, isFixedSize := field.Type.IsFixedSize()
+		isStatic = isStatic && isFixedSize
+		offset += size
+	}
+}
+
+// appendBitfield adds [field] to the previous [Bitfield], if any
+// and if the bits are not already used, or starts a new one
+func appendBitfield(fields []Field, field *types.Var, bits bitRange) []Field {
+	basic, isBasic := field.Type().Underlying().(*types.Basic)
+	if !isBasic {
+		panic(fmt.Sprintf("bitfield %s must have an integer type", field.Name()))
+	}
+	size, ok := newBinarySize(basic)
+	if !ok || basic.Info()&(types.IsInteger|types.IsBoolean) == 0 {
+		panic(fmt.Sprintf("bitfield %s must have an integer type", field.Name()))
+	}
+	member := BitfieldMember{origin: field.Type(), Name: field.Name(), First: bits.first, Last: bits.last}
+
+	if L := len(fields); L != 0 {
+		if bf, isBitfield := fields[L-1].Type.(Bitfield); isBitfield && !bf.overlaps(member) {
+			bf.Members = append(bf.Members, member)
+			fields[L-1].Type = bf
+			return fields
+		}
+	}
+
+	// the first member defines the storage
+	bf := Bitfield{Members: []BitfieldMember{member}, Storage: size}
+	return append(fields, Field{Name: field.Name(), Type: bf})
+}
+
 func (an *Analyser) createFromInterface(ty *types.Named, unionField *types.Var) Union {
 	itfName := ty.Obj().Name()
 	itf := ty.Underlying().(*types.Interface)
@@ -470,6 +1026,13 @@ func (an *Analyser) createFromInterface(ty *types.Named, unionField *types.Var)
 		panic(fmt.Sprintf("interface %s does not have any member", itfName))
 	}
 
+	// add the wrappers of the union, not yet analysed
+	wrappers := an.wrappersOf(ty)
+	if len(wrappers) != 0 && unionField == nil {
+		panic(fmt.Sprintf("unwrap is only supported for unions with an unionField tag (%s)", ty))
+	}
+	members = append(members[:len(members):len(members)], wrappers...)
+
 	out := Union{origin: ty}
 	for _, member := range members {
 		// analyse the concrete type
@@ -509,3 +1072,57 @@ func (an *Analyser) createFromInterface(ty *types.Named, unionField *types.Var)
 
 	return out
 }
+
+// wrappersOf returns the structs with the unwrap directive,
+// with a field of type [union], sorted by name
+func (an *Analyser) wrappersOf(union *types.Named) (out []*types.Named) {
+	for ty, cm := range an.commentsMap {
+		if !cm.unwrap || an.unwrapping[ty] {
+			continue
+		}
+		st, isStruct := ty.Underlying().(*types.Struct)
+		if !isStruct {
+			panic(fmt.Sprintf("unwrap directive is only supported for structs (%s)", ty))
+		}
+		for i := 0; i < st.NumFields(); i++ {
+			if types.Identical(st.Field(i).Type(), union) {
+				out = append(out, ty)
+				break
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Obj().Name() < out[j].Obj().Name() })
+	return out
+}
+
+// innerField returns the name of the field storing the inner value
+// of the wrapper [st], which must be the only offset to a union
+func innerField(st Struct) string {
+	var inner []string
+	for _, field := range st.Fields {
+		if of, isOffset := field.Type.(Offset); isOffset {
+			if _, isUnion := of.Target.(Union); isUnion && !of.IsPointer {
+				inner = append(inner, field.Name)
+			}
+		}
+	}
+	if len(inner) != 1 {
+		panic(fmt.Sprintf("wrapper %s must have exactly one offset to a union, got %v", st.origin.Obj().Name(), inner))
+	}
+	return inner[0]
+}
+
+// hasWrapper returns true if [ty] is a union with a wrapper member
+func hasWrapper(ty Type) bool {
+	if of, isOffset := ty.(Offset); isOffset {
+		ty = of.Target
+	}
+	if u, isUnion := ty.(Union); isUnion {
+		for _, member := range u.Members {
+			if member.Unwrap != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"go/ast"
 	"go/format"
 	"go/types"
+	"strings"
 	"testing"
 )
 
@@ -85,9 +86,45 @@ func TestInterfaces(t *testing.T) {
 }
 
 func TestConstructors(t *testing.T) {
-	if ana.constructors["fl32"] != types.Typ[types.Uint32] {
+	if ana.constructors["fl32"].storage != types.Typ[types.Uint32] {
 		t.Fatal(ana.constructors["fl32"])
 	}
+	if cons := ana.constructors["float214"]; cons.storage != types.Typ[types.Uint16] || !cons.isMethod || cons.isFallible {
+		t.Fatal(cons)
+	}
+	if cons := ana.constructors["markClass"]; cons.isMethod || !cons.isFallible {
+		t.Fatal(cons)
+	}
+	if cons := ana.constructors["nameID"]; !cons.isMethod || !cons.isFallible {
+		t.Fatal(cons)
+	}
+
+	ty := ana.Tables[ana.ByName("WithConstructors")]
+	if de := ty.Fields[2].Type.(DerivedFromBasic); de.Name != "nameID" || !de.IsMethod || !de.IsFallible {
+		t.Fatal(de)
+	}
+	if !IsFallible(ty.Fields[5].Type) {
+		t.Fatal(ty.Fields[5].Type)
+	}
+	if st := ty.Fields[6].Type.(Struct); !st.NeedsParseFunction() {
+		t.Fatal("structs with fallible constructors must be parsed with their parsing function")
+	}
+	if _, isFixedSize := ty.Fields[6].Type.IsFixedSize(); !isFixedSize {
+		t.Fatal("structs with checks may have a fixed size")
+	}
+
+	// invalid and incomplete constructors are reported
+	if _, has := ana.constructors["badConstructor"]; has {
+		t.Fatal("invalid constructor should be ignored")
+	}
+	var hasBad, hasReadOnly bool
+	for _, diagnostic := range ana.Diagnostics {
+		hasBad = hasBad || strings.Contains(diagnostic, "badConstructorFromUint")
+		hasReadOnly = hasReadOnly || strings.Contains(diagnostic, "missing readOnlyToUint")
+	}
+	if !hasBad || !hasReadOnly || len(ana.Diagnostics) != 2 {
+		t.Fatal(ana.Diagnostics)
+	}
 }
 
 func TestOffset(t *testing.T) {
@@ -182,11 +219,26 @@ func TestImplicitITF(t *testing.T) {
 		t.Fatal()
 	}
 
-	if len(ana.StandaloneUnions) != 1 {
+	if len(ana.StandaloneUnions) != 2 {
 		t.Fatal()
 	}
 }
 
+func TestImplicitITFAtOffset(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithImplicitITFAtOffset")]
+	u := ty.Fields[0].Type.(Offset).Target.(Union)
+	unionScheme, ok := u.UnionTag.(UnionTagImplicit)
+	if !ok {
+		t.Fatal()
+	}
+	if unionScheme.Offset != 4 {
+		t.Fatal(unionScheme.Offset)
+	}
+	if codes := unionScheme.TagsCode(); codes[0] != "1" || codes[1] != "32768" {
+		t.Fatal(codes)
+	}
+}
+
 func TestChildTypes(t *testing.T) {
 	m := ana.ChildTypes
 	if _, isChild := m[ana.ByName("PassArg")]; isChild {
@@ -221,3 +273,597 @@ func TestParseEnd(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestBitfields(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithBitfields")]
+	if len(ty.Fields) != 5 {
+		t.Fatal(ty.Fields)
+	}
+	bf1 := ty.Fields[1].Type.(Bitfield)
+	if bf1.Storage != Uint16 || len(bf1.Members) != 2 {
+		t.Fatal(bf1)
+	}
+	bf2 := ty.Fields[3].Type.(Bitfield)
+	if bf2.Storage != Byte || len(bf2.Members) != 3 || !bf2.Members[0].IsBool() {
+		t.Fatal(bf2)
+	}
+	if bf2.Members[1].Mask() != 0xF {
+		t.Fatal(bf2.Members[1].Mask())
+	}
+
+	// overlapping bits start a new storage
+	adjacent := ana.Tables[ana.ByName("WithAdjacentBitfields")]
+	if len(adjacent.Fields) != 3 {
+		t.Fatal(adjacent.Fields)
+	}
+	if size, _ := adjacent.IsFixedSize(); size != 5 {
+		t.Fatal(size)
+	}
+	if bf := adjacent.Fields[2].Type.(Bitfield); bf.Storage != Byte || !bf.Members[0].IsSigned() || bf2.Members[1].IsSigned() {
+		t.Fatal(bf)
+	}
+
+	// the storage is only counted once
+	if scopes := ty.Scopes(); len(scopes) != 2 || scopes[0].(StaticSizedFields).Size() != 7 {
+		t.Fatal(scopes)
+	}
+
+	uint8Type, boolType := types.Typ[types.Uint8], types.Typ[types.Bool]
+	for _, invalid := range []Bitfield{
+		{Storage: Byte, Members: []BitfieldMember{{origin: uint8Type, First: 0, Last: 3}, {origin: uint8Type, First: 3, Last: 5}}}, // overlap
+		{Storage: Byte, Members: []BitfieldMember{{origin: uint8Type, First: 4, Last: 8}}},                                         // out of storage
+		{Storage: Uint16, Members: []BitfieldMember{{origin: uint8Type, First: 0, Last: 8}}},                                       // out of field
+		{Storage: Byte, Members: []BitfieldMember{{origin: boolType, First: 0, Last: 1}}},                                          // out of field
+	} {
+		if err := invalid.validate(); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+}
+
+func TestBitStream(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithBitStream")]
+	sl := ty.Fields[2].Type.(Slice)
+	if !sl.IsBitStream() {
+		t.Fatal()
+	}
+	if code := sl.BitWidth.Code("item"); code != "8 * (((item.entryFormat >> 4) & 3) + 1)" {
+		t.Fatal(code)
+	}
+}
+
+func TestFieldExpression(t *testing.T) {
+	st := ana.ByName("WithBitStream").Underlying().(*types.Struct)
+	for _, test := range []struct {
+		src, expected string
+	}{
+		{"mapCount", "item.mapCount"},
+		{"mapCount*2 + pointCount", "item.mapCount*2 + item.pointCount"},
+		{"int(entryFormat) + externalArg", "int(item.entryFormat) + externalArg"},
+		{"entryFormat.method() + other.mapCount", "item.entryFormat.method() + other.mapCount"},
+	} {
+		if got := newFieldExpression(test.src, st).Code("item"); got != test.expected {
+			t.Fatalf("expected %s, got %s", test.expected, got)
+		}
+	}
+
+	// operands are converted to int
+	for _, test := range []struct {
+		src, expected string
+	}{
+		{"mapCount*2 + 1", "int(item.mapCount)*2 + 1"},
+		{"(entryFormat >> 4) & externalArg", "(int(item.entryFormat) >> 4) & int(externalArg)"},
+		{"int(entryFormat) + other.mapCount", "int(item.entryFormat) + int(other.mapCount)"},
+		{"entryFormat.method(pointCount)", "int(item.entryFormat.method(item.pointCount))"},
+	} {
+		if got := newFieldExpression(test.src, st).IntCode("item"); got != test.expected {
+			t.Fatalf("expected %s, got %s", test.expected, got)
+		}
+	}
+}
+
+func TestVarInts(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithVarInts")]
+	if vi := ty.Fields[1].Type.(VarInt); vi.Encoding != UIntBase128 {
+		t.Fatal(vi)
+	}
+	if sl := ty.Fields[4].Type.(Slice); sl.Elem.(VarInt).Encoding != U255UInt16 {
+		t.Fatal(sl)
+	}
+	if sl := ty.Fields[5].Type.(Slice); sl.Count != First255UInt16 {
+		t.Fatal(sl)
+	}
+	if vi := ty.Fields[7].Type.(VarInt); vi.Encoding != CFFOperand || vi.IsReal() {
+		t.Fatal(vi)
+	}
+	if vi := ty.Fields[8].Type.(VarInt); !vi.IsReal() {
+		t.Fatal(vi)
+	}
+}
+
+func TestCompressed(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithCompression")]
+	co := ty.Fields[1].Type.(Compressed)
+	if co.Algorithm != Zlib || !co.Target.(Slice).IsRawData() {
+		t.Fatal(co)
+	}
+	if code := co.UncompressedLength.Code("item"); code != "item.origLength" {
+		t.Fatal(code)
+	}
+	of := ty.Fields[3].Type.(Offset)
+	if co := of.Target.(Compressed); co.Algorithm != Gzip {
+		t.Fatal(co)
+	}
+	if co := ty.Fields[5].Type.(Compressed); co.Algorithm != Deflate {
+		t.Fatal(co)
+	}
+}
+
+func TestChecksums(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithChecksums")]
+	ch := ty.Fields[1].Checksum
+	if ch.Algorithm != OpenTypeChecksum || ch.IsTable() || ch.Length.Code("item") != "item.length" {
+		t.Fatal(ch)
+	}
+	ch = ty.Fields[4].Checksum
+	if ch.Algorithm != CRC32 || !ch.IsTable() || ch.Offset != 16 {
+		t.Fatal(ch)
+	}
+	if ty.Fields[0].Checksum.Algorithm != NoChecksum {
+		t.Fatal()
+	}
+}
+
+func TestReserved(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithReserved")]
+	if len(ty.Fields) != 11 {
+		t.Fatal(ty.Fields)
+	}
+	for i, size := range map[int]BinarySize{1: 2, 3: 3, 5: 2, 9: 3} {
+		re, ok := ty.Fields[i].Type.(Reserved)
+		if !ok || re.Size != size || !re.Strict {
+			t.Fatal(i, ty.Fields[i])
+		}
+	}
+	if pa, ok := ty.Fields[7].Type.(Padding); !ok || pa.Align != 4 || !pa.Strict {
+		t.Fatal(ty.Fields[7])
+	}
+
+	// strict checks are performed by the parsing function
+	strict := ana.Tables[ana.ByName("WithStrictElements")]
+	if !strict.Fields[0].Type.(Struct).NeedsParseFunction() {
+		t.Fatal("strict structs must be parsed with their parsing function")
+	}
+
+	ty = ana.Tables[ana.ByName("WithFixedReserved")]
+	if size, ok := ty.IsFixedSize(); !ok || size != 10 {
+		t.Fatal(size)
+	}
+	if re, ok := ty.Fields[1].Type.(Reserved); !ok || re.Size != 4 || re.Strict {
+		t.Fatal(ty.Fields[1])
+	}
+	if bf := ty.Fields[2].Type.(Bitfield); len(bf.Members) != 2 {
+		t.Fatal(bf)
+	}
+}
+
+func TestExpect(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithExpect")]
+	if ex := ty.Fields[1].Expect; len(ex) != 2 || ex[1].ExactString() != "1" {
+		t.Fatal(ex)
+	}
+	if ex := ty.Fields[2].Expect; len(ex) != 1 || ex[0].ExactString() != "1594834165" {
+		t.Fatal(ex)
+	}
+	if ex := ty.Fields[4].Expect; len(ex) != 3 || ex[0].ExactString() != "-1" {
+		t.Fatal(ex)
+	}
+	if len(ty.Fields[3].Expect) != 0 {
+		t.Fatal()
+	}
+	// the elements are checked by their parsing function
+	if !ty.Fields[5].Type.(Slice).Elem.(Struct).NeedsParseFunction() {
+		t.Fatal("structs with constant values must be parsed with their parsing function")
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithConstraints")]
+	if !ty.HasConstraints() {
+		t.Fatal()
+	}
+	// the elements are validated by their parsing function
+	records := ana.Tables[ana.ByName("WithConstrainedRecords")]
+	if !records.Fields[0].Type.(Slice).Elem.(Struct).NeedsParseFunction() {
+		t.Fatal("structs with constraints must be parsed with their parsing function")
+	}
+	if cs := ty.Fields[0].Constraints; len(cs.OneOf) != 3 || cs.Min != nil {
+		t.Fatal(cs)
+	}
+	if cs := ty.Fields[1].Constraints; cs.Min.ExactString() != "16" || cs.Max.ExactString() != "16384" {
+		t.Fatal(cs)
+	}
+	if cs := ty.Fields[2].Constraints; !cs.NonZero || cs.IsEmpty() {
+		t.Fatal(cs)
+	}
+	if cs := ty.Fields[4].Constraints; cs.LessThan.Code("item") != "numGlyphs" {
+		t.Fatal(cs)
+	}
+
+	if ana.Tables[ana.ByName("WithExpect")].HasConstraints() {
+		t.Fatal()
+	}
+}
+
+func TestSortedBy(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithSortedRecords")]
+	key := ty.Fields[0].Type.(Slice).SortedBy
+	if key.IsEmpty() || key.IsRange() || key.Start != "glyph" || !key.Check {
+		t.Fatal(key)
+	}
+	key = ty.Fields[1].Type.(Slice).SortedBy
+	if !key.IsRange() || key.End != "end" || key.Check {
+		t.Fatal(key)
+	}
+	if key.Origin().String() != "uint16" {
+		t.Fatal(key.Origin())
+	}
+	// records with checks may be sorted
+	if elem := ty.Fields[2].Type.(Slice).Elem.(Struct); !elem.NeedsParseFunction() {
+		t.Fatal(elem)
+	}
+}
+
+func TestMaps(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithMaps")]
+	m, ok := ty.Fields[0].Type.(Map)
+	if !ok || m.Key != "tag" || m.Records.Count != FirstUint16 {
+		t.Fatal(ty.Fields[0])
+	}
+	if _, isFixedSize := m.Records.Elem.IsFixedSize(); !isFixedSize {
+		t.Fatal()
+	}
+	if m = ty.Fields[2].Type.(Map); ResolveOffsetRelative(m) != Parent {
+		t.Fatal()
+	}
+	// keys may be imported types
+	if m = ty.Fields[3].Type.(Map); !IsScalar(m.Records.Elem.(Struct).Fields[0].Type.Origin()) {
+		t.Fatal(m)
+	}
+	// records may be stored at offsets
+	if m = ty.Fields[4].Type.(Map); m.Key != "format" {
+		t.Fatal(m)
+	} else if _, isOffset := m.Records.Elem.(Offset); !isOffset {
+		t.Fatal(m)
+	}
+	// the count prefix belongs to the fixed size scope
+	if scopes := ty.Scopes(); len(scopes) != 8 {
+		t.Fatal(scopes)
+	}
+}
+
+func TestStrings(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithStrings")]
+	st := ty.Fields[1].Type.(String)
+	if st.Encoding != MacRoman || st.Data.Count != FirstUint8 {
+		t.Fatal(st)
+	}
+	if st = ty.Fields[3].Type.(String); st.Encoding != UTF16BE || st.FixedLength != 16 {
+		t.Fatal(st)
+	}
+	if _, isFixedSize := st.IsFixedSize(); isFixedSize {
+		t.Fatal()
+	}
+	if st = ty.Fields[6].Type.(Offset).Target.(String); st.Data.Count != ComputedField || st.Data.CountExpr != "length" {
+		t.Fatal(st)
+	}
+	if st = ty.Fields[8].Type.(String); st.Encoding != UTF8 {
+		t.Fatal(st)
+	}
+}
+
+func TestByteWidth(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithByteWidth")]
+	sl := ty.Fields[2].Type.(Slice)
+	if code := sl.ByteWidth.Code("item"); code != "item.offSize" {
+		t.Fatal(code)
+	}
+	if _, isFixedSize := sl.IsFixedSize(); isFixedSize {
+		t.Fatal()
+	}
+	if sl = ty.Fields[5].Type.(Slice); sl.ByteWidth.IsEmpty() {
+		t.Fatal()
+	}
+	if _, isOffset := sl.Elem.(Offset); !isOffset {
+		t.Fatal(sl.Elem)
+	}
+
+	// CFF INDEX : the offSize is optional, and the counts are computed by methods
+	index := ana.Tables[ana.ByName("CFFIndex")]
+	if index.Fields[1].PresentIf.IsEmpty() {
+		t.Fatal()
+	}
+	if sl = index.Fields[2].Type.(Slice); sl.Count != ComputedField || sl.CountExpr != "offsetsCount()" {
+		t.Fatal(sl)
+	}
+}
+
+func TestUint24(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithUint24")]
+	if size, _ := ty.Fields[0].Type.IsFixedSize(); size != Uint24 {
+		t.Fatal(size)
+	}
+	sl := ty.Fields[1].Type.(Slice)
+	if sl.Count != FirstUint24 || sl.Count.Size() != Uint24 {
+		t.Fatal(sl.Count)
+	}
+	if size, _ := sl.Elem.IsFixedSize(); size != Uint24 {
+		t.Fatal(size)
+	}
+	if size, _ := ty.Fields[2].Type.IsFixedSize(); size != Uint16 {
+		t.Fatal(size)
+	}
+	if sl = ty.Fields[3].Type.(Slice); sl.Count != FirstUint8 || !sl.IsRawData() {
+		t.Fatal(sl)
+	}
+	if of := ty.Fields[4].Type.(Offset); of.Size != Uint24 {
+		t.Fatal(of)
+	}
+	if size, _ := ty.Fields[6].Type.IsFixedSize(); size != 2*Uint24 {
+		t.Fatal(size)
+	}
+}
+
+func TestBinarySize(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithBinarySize")]
+	if ba := ty.Fields[0].Type.(Basic); ba.Size != Uint16 || ba.Signed {
+		t.Fatal(ba)
+	}
+	if ba := ty.Fields[2].Type.(Basic); ba.Size != Uint24 || !ba.Signed {
+		t.Fatal(ba)
+	}
+	if ba := ty.Fields[4].Type.(Slice).Elem.(Basic); ba.Size != Uint16 || !ba.Signed {
+		t.Fatal(ba)
+	}
+	if size, _ := ty.Fields[6].Type.IsFixedSize(); size != 2*Uint24 {
+		t.Fatal(size)
+	}
+
+	for tag, expected := range map[string]binaryInt{
+		"3":      {size: Uint24},
+		"uint8":  {size: Byte},
+		"int24":  {size: Uint24, signed: true},
+		"int64":  {size: Uint64, signed: true},
+		"uint32": {size: Uint32},
+	} {
+		if got := parseBinarySize(tag); got != expected {
+			t.Fatalf("for %s, expected %v, got %v", tag, expected, got)
+		}
+	}
+}
+
+func TestFloats(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithFloats")]
+	for i, expected := range []struct {
+		encoding FloatEncoding
+		size     BinarySize
+	}{
+		{IEEE754, Uint32},
+		{IEEE754, Uint64},
+		{IEEE754, Uint32},
+		{F2Dot14, Uint16},
+		{Fixed, Uint32},
+	} {
+		ba := ty.Fields[i].Type.(Basic)
+		if size, _ := ba.IsFixedSize(); ba.FloatEncoding != expected.encoding || size != expected.size {
+			t.Fatal(ba)
+		}
+	}
+	if size, _ := ty.Fields[5].Type.IsFixedSize(); size != 4*Uint16 {
+		t.Fatal(size)
+	}
+	if ba := ty.Fields[6].Type.(Slice).Elem.(Basic); ba.FloatEncoding != Fixed {
+		t.Fatal(ba)
+	}
+}
+
+func TestEnums(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithEnums")]
+	format := ty.Fields[0].Type.(Basic)
+	if len(format.Enum) != 4 || format.Enum[3].Name() != "paintGlyph" {
+		t.Fatal(format.Enum)
+	}
+	if hidden := ty.Fields[1].Type.(Basic); !hidden.IsBool() || !hidden.Strict {
+		t.Fatal(hidden)
+	}
+	if version := ty.Fields[4].Type.(Basic); len(version.Enum) != 2 {
+		t.Fatal(version.Enum)
+	}
+
+	ty = ana.Tables[ana.ByName("WithBools")]
+	if a := ty.Fields[0].Type.(Basic); !a.IsBool() || a.Strict {
+		t.Fatal(a)
+	}
+
+	ty = ana.Tables[ana.ByName("WithEnumElements")]
+	if formats := ty.Fields[0].Type.(Array).Elem.(Basic); len(formats.Enum) != 4 {
+		t.Fatal(formats.Enum)
+	}
+	if flags := ty.Fields[1].Type.(Array).Elem.(Basic); !flags.Strict {
+		t.Fatal(flags)
+	}
+	if modes := ty.Fields[2].Type.(Slice); modes.IsRawData() || len(modes.Elem.(Basic).Enum) != 3 {
+		t.Fatal(modes)
+	}
+	if states := ty.Fields[3].Type.(Slice).Elem.(Basic); !states.Strict {
+		t.Fatal(states)
+	}
+	if st := ty.Fields[4].Type.(Struct); !st.NeedsParseFunction() {
+		t.Fatal("structs with enums must be parsed with their parsing function")
+	}
+	if _, isFixedSize := ty.Fields[4].Type.IsFixedSize(); !isFixedSize {
+		t.Fatal("structs with checks may have a fixed size")
+	}
+}
+
+func TestScalars(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithScalars")]
+	for _, field := range ty.Fields {
+		if !IsScalar(field.Type.Origin()) {
+			if sl, isSlice := field.Type.(Slice); !isSlice || !IsScalar(sl.Elem.Origin()) {
+				t.Fatal(field.Type)
+			}
+		}
+	}
+	if size, _ := ty.Fields[3].Type.IsFixedSize(); size != Uint16 {
+		t.Fatal(size)
+	}
+	if size, _ := ty.Fields[6].Type.IsFixedSize(); size != Uint24 {
+		t.Fatal(size)
+	}
+	if st := ty.Scopes()[0].(StaticSizedFields); st.Size() != 24 {
+		t.Fatal(st.Size())
+	}
+	// tags and dates are validated
+	if created, tag := ty.Fields[1].Type.(Basic), ty.Fields[2].Type.(Basic); !created.HasValidate || !tag.HasValidate {
+		t.Fatal(created, tag)
+	}
+	if version := ty.Fields[0].Type.(Basic); version.HasValidate {
+		t.Fatal(version)
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithCodecs")]
+	date := ty.Fields[0].Type.(Codec)
+	if date.Name != "time.Time" || date.Parse != "parseDate" || date.Write != "appendDate" {
+		t.Fatal(date)
+	}
+	if size, isFixed := date.IsFixedSize(); !isFixed || size != 8 {
+		t.Fatal(size)
+	}
+	// fixed size codecs are grouped
+	if fs := ty.Scopes()[0].(StaticSizedFields); len(fs) != 3 || fs.Size() != 18 {
+		t.Fatal(fs)
+	}
+	value := ty.Fields[3].Type.(Codec)
+	if _, isFixed := value.IsFixedSize(); isFixed || value.Write != "" {
+		t.Fatal(value)
+	}
+	if of := ty.Fields[5].Type.(Offset); of.Target.(Codec).Parse != "parseBigInt" {
+		t.Fatal(of)
+	}
+	if !IsFallible(ty.Fields[6].Type) {
+		t.Fatal(ty.Fields[6].Type)
+	}
+	if st := ty.Fields[7].Type.(Struct); !st.NeedsParseFunction() {
+		t.Fatal("structs with codecs must be parsed with their parsing function")
+	}
+	// structs with fixed size codecs are still fixed size
+	if size, isFixedSize := ty.Fields[7].Type.IsFixedSize(); !isFixedSize || size != 10 {
+		t.Fatal(size)
+	}
+	if key := ty.Fields[8].Type.(Slice).SortedBy; key.Start != "count" {
+		t.Fatal(key)
+	}
+}
+
+func TestOptionalFields(t *testing.T) {
+	record := ana.Tables[ana.ByName("valueRecord")]
+	if _, isFixed := record.IsFixedSize(); isFixed || !record.IsSizedByArguments() {
+		t.Fatal()
+	}
+	if scopes := record.Scopes(); len(scopes) != 2 {
+		t.Fatal(scopes)
+	} else if fs := scopes[0].(OptionalFields); len(fs) != 5 || fs[4].PresentIf.Source != "valueFormat&0x0010" {
+		t.Fatal(fs)
+	}
+
+	ty := ana.Tables[ana.ByName("WithOptionalFields")]
+	if ty.IsSizedByArguments() {
+		t.Fatal()
+	}
+	// the condition refers to a field, which must be read first
+	scopes := ty.Scopes()
+	if fs := scopes[len(scopes)-1].(OptionalFields); len(fs) != 2 || !fs[0].PresentIf.RefersToFields() {
+		t.Fatal(fs)
+	}
+	if _, isStatic := scopes[len(scopes)-2].(StaticSizedFields); !isStatic {
+		t.Fatal(scopes)
+	}
+}
+
+func TestSizedByArguments(t *testing.T) {
+	for _, name := range []string{"uniformRecord", "valueRecord", "SubElement"} {
+		if !ana.Tables[ana.ByName(name)].IsSizedByArguments() {
+			t.Fatal(name)
+		}
+	}
+	// fixed size structs and counts read from fields are excluded
+	for _, name := range []string{"withFixedSize", "Element", "WithOptionalFields", "WithByteWidth"} {
+		if ana.Tables[ana.ByName(name)].IsSizedByArguments() {
+			t.Fatal(name)
+		}
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	wrapper := ana.Tables[ana.ByName("lookupSubtableExtension")]
+	if wrapper.Unwrap != "inner" {
+		t.Fatal(wrapper.Unwrap)
+	}
+	// nested wrappers are not supported
+	if inner := wrapper.Fields[2].Type.(Offset).Target.(Union); len(inner.Members) != 2 {
+		t.Fatal(inner.Members)
+	}
+
+	ty := ana.Tables[ana.ByName("WithExtension")]
+	// the flag is not part of the binary layout
+	if len(ty.Fields) != 2 || ty.Fields[1].UnwrapFlag != "isExtension" {
+		t.Fatal(ty.Fields)
+	}
+	if members := ty.Fields[1].Type.(Union).Members; len(members) != 3 || members[2].Unwrap == "" {
+		t.Fatal(members)
+	}
+}
+
+func TestSkipped(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithSkipped")]
+	if len(ty.Fields) != 2 || ty.Fields[1].Name != "version" {
+		t.Fatal(ty.Fields)
+	}
+	fixed := ana.Tables[ana.ByName("WithFixedSkipped")]
+	if size, isFixed := fixed.IsFixedSize(); !isFixed || size != 6 {
+		t.Fatal(size)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	ty := ana.Tables[ana.ByName("WithHooks")]
+	if ty.ParseStart == nil || ty.ParseStart.WithArguments || ty.ParseStart.ReturnsError {
+		t.Fatal(ty.ParseStart)
+	}
+	if after := ty.Fields[0].After; after == nil || !after.WithArguments || !after.ReturnsError {
+		t.Fatal(after)
+	}
+	if after := ty.Fields[3].After; after == nil || after.WithArguments || after.ReturnsError {
+		t.Fatal(after)
+	}
+	if ty.Validate == nil || ty.Validate.WithArguments || !ty.Validate.ReturnsError {
+		t.Fatal(ty.Validate)
+	}
+
+	fixed := ana.Tables[ana.ByName("withFixedHooks")]
+	if _, isFixed := fixed.IsFixedSize(); !isFixed || !fixed.NeedsParseFunction() {
+		t.Fatal("structs with hooks must not be parsed with mustParse")
+	}
+	if fixed.Validate == nil || !fixed.Validate.WithArguments {
+		t.Fatal(fixed.Validate)
+	}
+
+	// arrays of elements with hooks are parsed element by element
+	array := ana.Tables[ana.ByName("WithHookedArray")].Fields[0].Type.(Array)
+	if _, isFixed := array.IsFixedSize(); !isFixed || !IsFallible(array) {
+		t.Fatal("arrays of elements with hooks must be parsed element by element")
+	}
+}
@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"fmt"
+	"go/types"
+	"strconv"
+	"strings"
+)
+
+// codecs are registered once for a package, with special
+// comments of the form
+//
+//	// binarygen: codec type=<importPath>.<Type> parse=<importPath>.<Func> [write=<importPath>.<Func>] [size=<n>]
+//
+// where the import path may be omitted for the current package.
+
+// fetchCodecs looks for the codec directives in the comments
+// of the package files
+func (an *Analyser) fetchCodecs() {
+	an.codecs = make(map[string]Codec)
+	for _, file := range an.pkg.Syntax {
+		for _, group := range file.Comments {
+			for _, comment := range group.List {
+				if _, value, ok := strings.Cut(comment.Text, " binarygen: codec "); ok {
+					codec := an.newCodec(value)
+					an.codecs[typeKey(codec.origin)] = codec
+				}
+			}
+		}
+	}
+}
+
+// typeKey returns the qualified name of named types,
+// or an empty string
+func typeKey(ty types.Type) string {
+	named, isNamed := ty.(*types.Named)
+	if !isNamed || named.Obj().Pkg() == nil {
+		return ""
+	}
+	return named.Obj().Pkg().Path() + "." + named.Obj().Name()
+}
+
+// lookup resolves the qualified name [name] in the current
+// package or in its imports.
+func (an *Analyser) lookup(name string) types.Object {
+	scope := an.pkg.Types.Scope()
+	if path, objName, isQualified := cutLast(name, "."); isQualified && path != an.pkg.PkgPath {
+		imported, ok := an.pkg.Imports[path]
+		if !ok {
+			panic(fmt.Sprintf("invalid codec directive: package %s is not imported", path))
+		}
+		scope, name = imported.Types.Scope(), objName
+	} else if isQualified {
+		name = objName
+	}
+	obj := scope.Lookup(name)
+	if obj == nil {
+		panic(fmt.Sprintf("invalid codec directive: %s not found", name))
+	}
+	return obj
+}
+
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// qualifiedName returns the name of [obj] as used in generated code
+func (an *Analyser) qualifiedName(obj types.Object) string {
+	if obj.Pkg() == an.pkg.Types {
+		return obj.Name()
+	}
+	return obj.Pkg().Name() + "." + obj.Name()
+}
+
+func (an *Analyser) newCodec(directive string) Codec {
+	var out Codec
+	var parse, write *types.Func
+	for _, chunk := range strings.Fields(directive) {
+		key, value, _ := strings.Cut(chunk, "=")
+		switch key {
+		case "type":
+			tn, isTypeName := an.lookup(value).(*types.TypeName)
+			if !isTypeName {
+				panic(fmt.Sprintf("invalid codec directive: %s is not a type", value))
+			}
+			out.origin = tn.Type()
+			out.Name = an.qualifiedName(tn)
+		case "parse", "write":
+			fn, isFunc := an.lookup(value).(*types.Func)
+			if !isFunc {
+				panic(fmt.Sprintf("invalid codec directive: %s is not a function", value))
+			}
+			if key == "parse" {
+				parse = fn
+			} else {
+				write = fn
+			}
+		case "size":
+			size, err := strconv.Atoi(value)
+			if err != nil || size <= 0 {
+				panic("invalid codec directive: invalid size " + value)
+			}
+			out.Size = BinarySize(size)
+		default:
+			panic("invalid codec directive: unknown key " + key)
+		}
+	}
+	if out.origin == nil || parse == nil {
+		panic("invalid codec directive: type and parse are required")
+	}
+
+	// parse : func(src []byte) (T, int, error)
+	sig := parse.Type().(*types.Signature)
+	bytesType := types.NewSlice(types.Typ[types.Byte])
+	if sig.Params().Len() != 1 || !types.Identical(sig.Params().At(0).Type(), bytesType) ||
+		sig.Results().Len() != 3 || !types.Identical(sig.Results().At(0).Type(), out.origin) ||
+		!types.Identical(sig.Results().At(1).Type(), types.Typ[types.Int]) || !types.Identical(sig.Results().At(2).Type(), errorType) {
+		panic(fmt.Sprintf("invalid codec directive: %s must have signature func([]byte) (%s, int, error)", parse.Name(), out.origin))
+	}
+	out.Parse = an.qualifiedName(parse)
+
+	// write : func(dst []byte, value T) []byte
+	if write != nil {
+		sig := write.Type().(*types.Signature)
+		if sig.Params().Len() != 2 || !types.Identical(sig.Params().At(0).Type(), bytesType) ||
+			!types.Identical(sig.Params().At(1).Type(), out.origin) ||
+			sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), bytesType) {
+			panic(fmt.Sprintf("invalid codec directive: %s must have signature func([]byte, %s) []byte", write.Name(), out.origin))
+		}
+		out.Write = an.qualifiedName(write)
+	}
+	return out
+}
@@ -0,0 +1,164 @@
+package analysis
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// constructor is a custom conversion from the binary storage,
+// either a <typeString>FromUint function, with signature
+//
+//	func (v uintXX) T  or  func (v uintXX) (T, error)
+//
+// or a fromUint method, with signature
+//
+//	func (t *T) fromUint(v uintXX)  or  func (t *T) fromUint(v uintXX) error
+type constructor struct {
+	storage    *types.Basic
+	isMethod   bool
+	isFallible bool
+}
+
+// diagnose records a non fatal issue
+func (an *Analyser) diagnose(pos token.Pos, format string, args ...interface{}) {
+	an.Diagnostics = append(an.Diagnostics, fmt.Sprintf("%s: %s", an.pkg.Fset.Position(pos), fmt.Sprintf(format, args...)))
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// storageParam returns the storage type of the single parameter of [sig],
+// or an error
+func storageParam(sig *types.Signature) (*types.Basic, error) {
+	if sig.Params().Len() != 1 {
+		return nil, fmt.Errorf("expected one argument, got %d", sig.Params().Len())
+	}
+	basic, isBasic := sig.Params().At(0).Type().(*types.Basic)
+	if !isBasic || basic.Info()&types.IsUnsigned == 0 {
+		return nil, fmt.Errorf("expected an unsigned integer argument, got %s", sig.Params().At(0).Type())
+	}
+	if _, ok := newBinarySize(basic); !ok {
+		return nil, fmt.Errorf("unsupported argument type %s", basic)
+	}
+	return basic, nil
+}
+
+// newFuncConstructor checks the signature of a <typeString>FromUint function
+func newFuncConstructor(sig *types.Signature) (constructor, error) {
+	storage, err := storageParam(sig)
+	if err != nil {
+		return constructor{}, err
+	}
+	out := constructor{storage: storage}
+	switch results := sig.Results(); {
+	case results.Len() == 1:
+	case results.Len() == 2 && types.Identical(results.At(1).Type(), errorType):
+		out.isFallible = true
+	default:
+		return constructor{}, fmt.Errorf("expected (T) or (T, error) results, got %s", results)
+	}
+	return out, nil
+}
+
+// newMethodConstructor checks the signature of a fromUint method
+func newMethodConstructor(sig *types.Signature) (constructor, error) {
+	if _, isPointer := sig.Recv().Type().(*types.Pointer); !isPointer {
+		return constructor{}, fmt.Errorf("expected a pointer receiver")
+	}
+	storage, err := storageParam(sig)
+	if err != nil {
+		return constructor{}, err
+	}
+	out := constructor{storage: storage, isMethod: true}
+	switch results := sig.Results(); {
+	case results.Len() == 0:
+	case results.Len() == 1 && types.Identical(results.At(0).Type(), errorType):
+		out.isFallible = true
+	default:
+		return constructor{}, fmt.Errorf("expected no result or an error, got %s", results)
+	}
+	return out, nil
+}
+
+// fetchConstructors looks for custom constructors, reporting
+// invalid signatures as diagnostics.
+func (an *Analyser) fetchConstructors() {
+	an.constructors = make(map[string]constructor)
+
+	scope := an.pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		switch obj := scope.Lookup(name).(type) {
+		case *types.Func:
+			// look for <...>FromUint functions
+			typeName := strings.TrimSuffix(obj.Name(), "FromUint")
+			if typeName == obj.Name() || typeName == "" {
+				continue
+			}
+			cons, err := newFuncConstructor(obj.Type().(*types.Signature))
+			if err != nil {
+				an.diagnose(obj.Pos(), "invalid constructor %s: %s", obj.Name(), err)
+				continue
+			}
+			an.constructors[typeName] = cons
+		case *types.TypeName:
+			if obj.IsAlias() {
+				continue
+			}
+			named := obj.Type().(*types.Named)
+			for i := 0; i < named.NumMethods(); i++ {
+				method := named.Method(i)
+				if method.Name() != "fromUint" {
+					continue
+				}
+				cons, err := newMethodConstructor(method.Type().(*types.Signature))
+				if err != nil {
+					an.diagnose(method.Pos(), "invalid constructor %s.fromUint: %s", obj.Name(), err)
+					continue
+				}
+				if _, isBasic := named.Underlying().(*types.Basic); !isBasic {
+					continue // other types are not supported
+				}
+				an.constructors[obj.Name()] = cons
+			}
+		}
+	}
+
+	typeNames := make([]string, 0, len(an.constructors))
+	for typeName := range an.constructors {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+	for _, typeName := range typeNames {
+		an.checkToUint(typeName, an.constructors[typeName])
+	}
+}
+
+// checkToUint checks that the conversion used when writing
+// [typeName] is defined, with the same storage as the constructor
+func (an *Analyser) checkToUint(typeName string, cons constructor) {
+	scope := an.pkg.Types.Scope()
+	var (
+		sig *types.Signature
+		pos token.Pos
+	)
+	if fn, isFunc := scope.Lookup(typeName + "ToUint").(*types.Func); isFunc {
+		sig, pos = fn.Type().(*types.Signature), fn.Pos()
+	} else if tn, isTypeName := scope.Lookup(typeName).(*types.TypeName); isTypeName {
+		if method, _, _ := types.LookupFieldOrMethod(tn.Type(), true, tn.Pkg(), "toUint"); method != nil {
+			sig, pos = method.Type().(*types.Signature), method.Pos()
+		}
+	}
+	if sig == nil {
+		pos = token.NoPos
+		if obj := scope.Lookup(typeName); obj != nil {
+			pos = obj.Pos()
+		}
+		an.diagnose(pos, "missing %sToUint function (or toUint method) for type %s", typeName, typeName)
+		return
+	}
+	if results := sig.Results(); results.Len() != 1 || !types.Identical(results.At(0).Type(), cons.storage) {
+		an.diagnose(pos, "invalid conversion for type %s: expected %s result, got %s", typeName, cons.storage, results)
+	}
+}
@@ -0,0 +1,131 @@
+package analysis
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// FieldExpression is a Go expression provided by a struct tag,
+// which may refer to the (previous) fields of the enclosing struct,
+// or to the arguments of the parsing function.
+type FieldExpression struct {
+	// Source is the expression, as written in the tag
+	Source string
+
+	// byte offsets in [Source] of the identifiers
+	// refering to a struct field
+	fieldRefs []int
+
+	// byte ranges in [Source] of the identifiers (or selectors)
+	// used as values, which may be converted to int
+	operands [][2]int
+}
+
+// newFieldExpression parses [src] and resolves the identifiers
+// matching a field of [st].
+func newFieldExpression(src string, st *types.Struct) FieldExpression {
+	fset := token.NewFileSet()
+	expr, err := parser.ParseExprFrom(fset, "", src, 0)
+	if err != nil {
+		panic("invalid expression " + src + ": " + err.Error())
+	}
+
+	fields := map[string]bool{}
+	for i := 0; i < st.NumFields(); i++ {
+		fields[st.Field(i).Name()] = true
+	}
+
+	out := FieldExpression{Source: src}
+	// withOperands is false inside function calls,
+	// whose arguments must keep their type
+	var inspect func(n ast.Node, withOperands bool) bool
+	inspect = func(n ast.Node, withOperands bool) bool {
+		addOperand := func() {
+			if withOperands {
+				out.operands = append(out.operands, [2]int{fset.Position(n.Pos()).Offset, fset.Position(n.End()).Offset})
+			}
+		}
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			// int conversions are kept as it is
+			if ident, ok := n.Fun.(*ast.Ident); !ok || ident.Name != "int" {
+				addOperand()
+			}
+			for _, child := range append([]ast.Expr{n.Fun}, n.Args...) {
+				ast.Inspect(child, func(n ast.Node) bool { return inspect(n, false) })
+			}
+			return false
+		case *ast.SelectorExpr:
+			addOperand()
+			// only the left part may refer to a field
+			ast.Inspect(n.X, func(n ast.Node) bool {
+				if ident, ok := n.(*ast.Ident); ok && fields[ident.Name] {
+					out.fieldRefs = append(out.fieldRefs, fset.Position(ident.Pos()).Offset)
+				}
+				return true
+			})
+			return false
+		case *ast.Ident:
+			if fields[n.Name] {
+				out.fieldRefs = append(out.fieldRefs, fset.Position(n.Pos()).Offset)
+			}
+			if n.Name != "true" && n.Name != "false" && n.Name != "nil" {
+				addOperand()
+			}
+		}
+		return true
+	}
+	ast.Inspect(expr, func(n ast.Node) bool { return inspect(n, true) })
+	sort.Ints(out.fieldRefs)
+	sort.Slice(out.operands, func(i, j int) bool { return out.operands[i][0] < out.operands[j][0] })
+
+	return out
+}
+
+// IsEmpty returns true if no expression is provided.
+func (fe FieldExpression) IsEmpty() bool { return fe.Source == "" }
+
+// RefersToFields returns true if the expression uses
+// at least one field of the struct.
+func (fe FieldExpression) RefersToFields() bool { return len(fe.fieldRefs) != 0 }
+
+// Code returns the Go code for the expression, where
+// fields are accessed with <objectVar>.<field>
+func (fe FieldExpression) Code(objectVar string) string {
+	var (
+		out  strings.Builder
+		last int
+	)
+	for _, ref := range fe.fieldRefs {
+		out.WriteString(fe.Source[last:ref])
+		out.WriteString(objectVar + ".")
+		last = ref
+	}
+	out.WriteString(fe.Source[last:])
+	return out.String()
+}
+
+// IntCode is the same as [Code], but each operand is
+// converted to int, so that arithmetic on narrow types may not overflow.
+func (fe FieldExpression) IntCode(objectVar string) string {
+	inserts := map[int]string{} // by offset in Source
+	for _, operand := range fe.operands {
+		inserts[operand[0]] += "int("
+		inserts[operand[1]] = ")" + inserts[operand[1]]
+	}
+	for _, ref := range fe.fieldRefs {
+		inserts[ref] += objectVar + "."
+	}
+	var out strings.Builder
+	for i := 0; i <= len(fe.Source); i++ {
+		out.WriteString(inserts[i])
+		if i < len(fe.Source) {
+			out.WriteByte(fe.Source[i])
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,104 @@
+package analysis
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hook is a user written method, called by the parsing function :
+//
+//   - parseStart(src []byte, <arguments>) [error], before reading any field
+//   - after<Field>(<arguments>) [error], right after the field is read
+//   - validate(<arguments>) error, at the end of the parsing function
+//
+// where <arguments> is either empty or the list of the arguments
+// of the parsing function (see [Struct.Arguments]).
+type Hook struct {
+	Name string
+
+	// WithArguments is true if the method receives the
+	// arguments of the parsing function
+	WithArguments bool
+
+	// ReturnsError is true if the method returns an error,
+	// which is then returned by the parsing function
+	ReturnsError bool
+}
+
+// hookField returns the field name for after<Field> methods
+func hookField(methodName string) (string, bool) {
+	field := strings.TrimPrefix(methodName, "after")
+	if field == methodName || field == "" {
+		return "", false
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return field, unicode.IsUpper(r)
+}
+
+// newHook checks the signature of the hook [m], whose parameters
+// must start with [params], optionally followed by [args]
+func (an *Analyser) newHook(m *types.Func, params []types.Type, args []Argument) Hook {
+	sig := m.Type().(*types.Signature)
+	out := Hook{Name: m.Name()}
+	err := func() error {
+		if _, isPointer := sig.Recv().Type().(*types.Pointer); !isPointer && m.Name() != "validate" {
+			return fmt.Errorf("expected a pointer receiver")
+		}
+
+		switch results := sig.Results(); {
+		case results.Len() == 0 && m.Name() != "validate":
+		case results.Len() == 1 && types.Identical(results.At(0).Type(), errorType):
+			out.ReturnsError = true
+		default:
+			if m.Name() == "validate" {
+				return fmt.Errorf("expected an error result, got %s", results)
+			}
+			return fmt.Errorf("expected no result or an error, got %s", results)
+		}
+
+		got := sig.Params()
+		for i, param := range params {
+			if got.Len() <= i || !types.Identical(got.At(i).Type(), param) {
+				return fmt.Errorf("expected %s as parameter %d", param, i+1)
+			}
+		}
+		switch got.Len() - len(params) {
+		case 0:
+		case len(args):
+			for i, arg := range args {
+				if ty := an.typeString(got.At(len(params) + i).Type()); ty != arg.TypeName {
+					return fmt.Errorf("expected %s for argument %s, got %s", arg.TypeName, arg.VariableName, ty)
+				}
+			}
+			out.WithArguments = len(args) != 0
+		default:
+			return fmt.Errorf("expected no argument or the %d arguments of the parsing function, got %d",
+				len(args), got.Len()-len(params))
+		}
+		return nil
+	}()
+	if err != nil {
+		panic(fmt.Sprintf("invalid hook %s: %s", m, err))
+	}
+	return out
+}
+
+// typeString returns the representation of [ty] used
+// in the special comment defining arguments
+func (an *Analyser) typeString(ty types.Type) string {
+	return types.TypeString(ty, func(pkg *types.Package) string {
+		if pkg == an.pkg.Types {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// isGenerated returns true if [obj] is defined in a generated file,
+// which must be ignored when looking for user written methods
+func (an *Analyser) isGenerated(obj types.Object) bool {
+	return strings.HasSuffix(an.pkg.Fset.Position(obj.Pos()).Filename, "_gen.go")
+}
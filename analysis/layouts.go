@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"fmt"
 	"go/constant"
 	"go/types"
 )
@@ -16,6 +17,9 @@ const (
 	Uint64
 )
 
+// Uint24 is used by some tables, as in COLR version 1
+const Uint24 BinarySize = 3
+
 func newBinarySize(t *types.Basic) (BinarySize, bool) {
 	switch t.Kind() {
 	case types.Bool, types.Int8, types.Uint8:
@@ -53,6 +57,14 @@ func (t Array) Origin() types.Type            { return t.origin }
 func (t Slice) Origin() types.Type            { return t.origin }
 func (t Union) Origin() types.Type            { return t.origin }
 func (t Opaque) Origin() types.Type           { return t.origin }
+func (t Bitfield) Origin() types.Type         { return t.Members[0].origin }
+func (t VarInt) Origin() types.Type           { return t.origin }
+func (t Compressed) Origin() types.Type       { return t.Target.Origin() }
+func (t Reserved) Origin() types.Type         { return t.origin }
+func (t Codec) Origin() types.Type            { return t.origin }
+func (t Padding) Origin() types.Type          { return nil }
+func (t Map) Origin() types.Type              { return t.origin }
+func (t String) Origin() types.Type           { return t.origin }
 
 // Struct defines the the binary layout
 // of a struct
@@ -64,6 +76,20 @@ type Struct struct {
 	// requires data not provided in the input slice
 	Arguments []Argument
 
+	// Unwrap is not empty for wrapper structs, defined
+	// with the special comment // binarygen: unwrap.
+	// It is the name of the field storing the inner value (an offset to a union),
+	// which is stored in place of the wrapper when it is a union member.
+	Unwrap string
+
+	// ParseStart is non nil if the table has a
+	// "parseStart" method, called before reading the fields
+	ParseStart *Hook
+
+	// Validate is non nil if the table has a "validate"
+	// method, called at the end of parsing
+	Validate *Hook
+
 	// HasParseEnd is non nil if the table has an
 	// additional "parseEnd" method which must be called
 	// at the end of parsing
@@ -92,14 +118,91 @@ type Field struct {
 	// Non zero if the offset must be resolved into
 	// the parent (or grand-parent) slice
 	OffsetRelativeTo OffsetRelative
+
+	// Non zero for (uint32) fields storing a checksum
+	Checksum Checksum
+
+	// Non empty for (integer) fields whose value must be
+	// one of the given constants. The first one is the
+	// value to write.
+	Expect []constant.Value
+
+	// Constraints are checked by the generated validate method
+	Constraints Constraints
+
+	// Non empty for optional fields, only present
+	// in binary files when the expression is non zero.
+	// The expression may refer to arguments or to previous fields.
+	PresentIf FieldExpression
+
+	// Non empty for union fields with wrapper members : the (bool) field
+	// set to true when the value was found in a wrapper. It is not part of the binary layout.
+	UnwrapFlag string
+
+	// After is non nil if the struct has an "after<Field>"
+	// method, called right after the field is read
+	After *Hook
+}
+
+// Constraints are declarative checks on the value of an integer
+// field, or on each element of a slice or array of integers.
+// They are not checked while parsing the field, but
+// by a separated method, called at the end of the parsing function.
+type Constraints struct {
+	// Min and Max are inclusive bounds, or nil
+	Min, Max constant.Value
+
+	// OneOf is not empty if the value must be one of the given constants
+	OneOf []constant.Value
+
+	// NonZero is true if the value must not be zero
+	NonZero bool
+
+	// LessThan is an (exclusive) upper bound, usually
+	// refering to an other field or to an argument
+	LessThan FieldExpression
+}
+
+// IsEmpty returns true if no constraint is provided.
+func (cs Constraints) IsEmpty() bool {
+	return cs.Min == nil && cs.Max == nil && len(cs.OneOf) == 0 && !cs.NonZero && cs.LessThan.IsEmpty()
+}
+
+// HasConstraints returns true if at least one field has [Constraints].
+func (st Struct) HasConstraints() bool {
+	for _, field := range st.Fields {
+		if !field.Constraints.IsEmpty() {
+			return true
+		}
+	}
+	return false
 }
 
+// Checksum describes a field storing the checksum
+// of a range of bytes.
+type Checksum struct {
+	Algorithm ChecksumAlgorithm
+
+	// Start and Length are the expressions defining the checked range,
+	// in the slice used to resolve offsets.
+	// If they are empty, the whole table is checked,
+	// with the bytes of the checksum field itself set to zero.
+	Start, Length FieldExpression
+
+	// Offset is the position of the checksum field in its table,
+	// only used when the whole table is checked.
+	Offset BinarySize
+}
+
+// IsTable returns true if the checked range is the whole table
+func (ch Checksum) IsTable() bool { return ch.Start.IsEmpty() }
+
 // IsFixedSize returns true if all the fields have fixed size.
 func (st Struct) IsFixedSize() (BinarySize, bool) {
 	var totalSize BinarySize
 	for _, field := range st.Fields {
 		size, ok := field.Type.IsFixedSize()
-		if !ok {
+		if !ok || !field.PresentIf.IsEmpty() {
 			return 0, false
 		}
 		totalSize += size
@@ -107,6 +210,110 @@ func (st Struct) IsFixedSize() (BinarySize, bool) {
 	return totalSize, true
 }
 
+// NeedsParseFunction returns true if parsing [st] may fail once its length
+// is checked, because of hooks or checks on the fields. Since mustParse methods
+// do not return errors, such structs are always parsed with their parsing function,
+// even when they have a fixed size.
+func (st Struct) NeedsParseFunction() bool {
+	if st.ParseStart != nil || st.Validate != nil || st.HasConstraints() {
+		return true
+	}
+	for _, field := range st.Fields {
+		if field.After != nil {
+			return true
+		}
+		if re, isReserved := field.Type.(Reserved); isReserved && re.Strict {
+			return true
+		}
+		if len(field.Expect) != 0 || HasValueChecks(field.Type) || IsFallible(field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFallible returns true for the fixed size types whose parsing
+// may fail once their length is checked : fallible constructors,
+// codecs, structs with checks (see [Struct.NeedsParseFunction]),
+// and the arrays of such elements.
+func IsFallible(ty Type) bool {
+	switch ty := ty.(type) {
+	case Struct:
+		return ty.NeedsParseFunction()
+	case DerivedFromBasic:
+		return ty.IsFallible
+	case Codec:
+		return true
+	case Array:
+		return IsFallible(ty.Elem)
+	}
+	return false
+}
+
+// HasValueChecks returns true for the enums, strict bools and
+// scalars with a Validate method, and the arrays of such elements,
+// whose values are checked once read.
+func HasValueChecks(ty Type) bool {
+	switch ty := ty.(type) {
+	case Basic:
+		return len(ty.Enum) != 0 || ty.Strict || ty.HasValidate
+	case Array:
+		return HasValueChecks(ty.Elem)
+	}
+	return false
+}
+
+// IsSizedByArguments returns true for structs without static size,
+// but whose size only depends on the arguments of the parsing function,
+// so that it is the same for all the elements of a slice.
+// This is the case when each field is either :
+//   - a static sized field or an offset (whose target is resolved after),
+//   - an optional field, whose condition does not refer to fields,
+//   - a slice whose length (and element width) is provided by arguments,
+//   - a struct sized by arguments, not receiving arguments from fields.
+func (st Struct) IsSizedByArguments() bool {
+	if _, isFixedSize := st.IsFixedSize(); isFixedSize {
+		return false
+	}
+	for _, field := range st.Fields {
+		if field.PresentIf.RefersToFields() || !isSizedByArguments(field) {
+			return false
+		}
+	}
+	return true
+}
+
+func isSizedByArguments(field Field) bool {
+	if _, isFixedSize := field.Type.IsFixedSize(); isFixedSize {
+		return true
+	}
+	switch ty := field.Type.(type) {
+	case Offset:
+		return true
+	case Slice:
+		return ty.isSizedByArguments()
+	case Struct:
+		return len(field.ArgumentsProvidedByFields) == 0 && ty.IsSizedByArguments()
+	default:
+		return false
+	}
+}
+
+// isSizedByArguments returns true if the length of the slice is provided
+// as argument, and its elements have a static size, or a width
+// given by an expression not refering to fields.
+func (sl Slice) isSizedByArguments() bool {
+	if sl.Count != NoLength || sl.SubsliceStart == AtStart ||
+		sl.BitWidth.RefersToFields() || sl.ByteWidth.RefersToFields() {
+		return false
+	}
+	if _, isOffset := sl.Elem.(Offset); isOffset {
+		return true
+	}
+	_, isFixedSize := sl.Elem.IsFixedSize()
+	return isFixedSize
+}
+
 // ResolveOffsetRelative return the union flag of all
 // the fields.
 func ResolveOffsetRelative(ty Type) OffsetRelative {
@@ -115,8 +322,14 @@ func ResolveOffsetRelative(ty Type) OffsetRelative {
 		return ty.resolveOffsetRelative()
 	case Slice:
 		return ResolveOffsetRelative(ty.Elem)
+	case Array:
+		return ResolveOffsetRelative(ty.Elem)
+	case Map:
+		return ResolveOffsetRelative(ty.Records)
 	case Offset:
 		return ResolveOffsetRelative(ty.Target)
+	case Compressed:
+		return ResolveOffsetRelative(ty.Target)
 	case Union:
 		var out OffsetRelative
 		for _, member := range ty.Members {
@@ -149,15 +362,46 @@ func (st Struct) resolveOffsetRelative() (out OffsetRelative) {
 // convertible from and to uintXX
 type Basic struct {
 	origin types.Type // may be named, but with underlying Basic
+
+	// Size is the size as read and written in binary files,
+	// if it differs from the size of the Go type, or 0
+	Size BinarySize
+	// Signed is true if the value stored on [Size] bytes is a signed
+	// integer, which must be sign extended.
+	Signed bool
+
+	// FloatEncoding is only used for float numbers
+	FloatEncoding FloatEncoding
+
+	// Strict is only used for bools, which must then be 0 or 1
+	// (instead of non zero meaning true)
+	Strict bool
+
+	// Enum is not empty for named integer types whose values
+	// are restricted to the declared constants, sorted by value.
+	Enum []*types.Const
+
+	// HasValidate is true for the types of [ScalarsPackage]
+	// with a Validate() error method, called once read
+	HasValidate bool
+}
+
+// IsBool returns true for booleans, stored as an uint8
+func (ba Basic) IsBool() bool {
+	return ba.origin.Underlying().(*types.Basic).Info()&types.IsBoolean != 0
 }
 
 func (ba Basic) IsFixedSize() (BinarySize, bool) {
+	if ba.Size != 0 {
+		return ba.Size, true
+	}
 	return newBinarySize(ba.origin.Underlying().(*types.Basic))
 }
 
 // DerivedFromBasic is stored as a an uintXX, but
 // uses custom constructor to perform the convertion :
-// <typeString>FromUintXX ; <typeString>ToUintXX
+// <typeString>FromUintXX ; <typeString>ToUintXX,
+// or the methods (*<typeString>).fromUint ; <typeString>.toUint
 type DerivedFromBasic struct {
 	origin types.Type // may be named, but with underlying Basic
 
@@ -168,12 +412,153 @@ type DerivedFromBasic struct {
 
 	// Size is the size as read and written in binary files
 	Size BinarySize
+
+	// IsMethod is true if the constructor is the fromUint method
+	IsMethod bool
+
+	// IsFallible is true if the constructor also returns an error
+	IsFallible bool
 }
 
 func (de DerivedFromBasic) IsFixedSize() (BinarySize, bool) {
 	return de.Size, true
 }
 
+// Bitfield is a group of (contiguous) struct fields packed
+// in the same integer, each of them using a range of bits.
+// The storage is the one of the first field of the group.
+type Bitfield struct {
+	Members []BitfieldMember
+
+	// Storage is the size of the integer holding the members
+	Storage BinarySize
+}
+
+func (bf Bitfield) IsFixedSize() (BinarySize, bool) { return bf.Storage, true }
+
+// BitfieldMember is one of the fields stored in a [Bitfield]
+type BitfieldMember struct {
+	origin types.Type // with underlying Basic
+
+	Name string
+
+	// First and Last are the (inclusive) positions of the bits
+	// used by the field, 0 being the least significant bit
+	First, Last int
+}
+
+func (bm BitfieldMember) Origin() types.Type { return bm.origin }
+
+// Mask returns the mask to apply, once the storage
+// has been shifted by [First]
+func (bm BitfieldMember) Mask() uint64 {
+	return 1<<(bm.Last-bm.First+1) - 1
+}
+
+// IsBool returns true if the field is a boolean flag.
+func (bm BitfieldMember) IsBool() bool {
+	return bm.origin.Underlying().(*types.Basic).Kind() == types.Bool
+}
+
+// IsSigned returns true if the field has a signed integer type,
+// whose value is sign extended from its bits.
+func (bm BitfieldMember) IsSigned() bool {
+	return bm.origin.Underlying().(*types.Basic).Info()&(types.IsInteger|types.IsUnsigned) == types.IsInteger
+}
+
+// check that the members do not overlap and
+// fit in the storage and in their Go type
+// overlaps returns true if some bits of [member]
+// are already used by the members of [bf]
+func (bf Bitfield) overlaps(member BitfieldMember) bool {
+	for _, other := range bf.Members {
+		if member.First <= other.Last && other.First <= member.Last {
+			return true
+		}
+	}
+	return false
+}
+
+func (bf Bitfield) validate() error {
+	var used uint64
+	for _, member := range bf.Members {
+		goSize, _ := newBinarySize(member.origin.Underlying().(*types.Basic))
+		goBits := 8 * int(goSize)
+		if member.IsBool() {
+			goBits = 1
+		}
+		if member.Last >= 8*int(bf.Storage) {
+			return fmt.Errorf("bits %d-%d of field %s are out of the %d-bit storage", member.First, member.Last, member.Name, 8*bf.Storage)
+		}
+		if member.Last-member.First+1 > goBits {
+			return fmt.Errorf("bits %d-%d do not fit in field %s", member.First, member.Last, member.Name)
+		}
+		mask := member.Mask() << member.First
+		if used&mask != 0 {
+			return fmt.Errorf("bits %d-%d of field %s overlap previous fields", member.First, member.Last, member.Name)
+		}
+		used |= mask
+	}
+	return nil
+}
+
+// VarInt is a number stored with a variable-length encoding.
+type VarInt struct {
+	origin types.Type // may be named, but with underlying Basic
+
+	Encoding VarIntEncoding
+}
+
+func (VarInt) IsFixedSize() (BinarySize, bool) { return 0, false }
+
+// IsReal returns true if the Go type is a float.
+// It is only supported for [CFFOperand].
+func (vi VarInt) IsReal() bool {
+	return vi.origin.Underlying().(*types.Basic).Info()&types.IsFloat != 0
+}
+
+// Compressed is a struct or raw data stored in compressed form.
+// The compressed data is delimited by the compression format itself.
+type Compressed struct {
+	// Target is the uncompressed type, either a [Struct]
+	// or raw data (see [Slice.IsRawData])
+	Target Type
+
+	Algorithm Compression
+
+	// UncompressedLength is the length of the data once
+	// uncompressed, which is also used as limit when inflating.
+	UncompressedLength FieldExpression
+}
+
+func (Compressed) IsFixedSize() (BinarySize, bool) { return 0, false }
+
+// Reserved is a range of bytes which is skipped when parsing,
+// used for reserved fields and padding.
+type Reserved struct {
+	origin types.Type
+
+	Size BinarySize
+
+	// Strict is true if the bytes must be zero
+	Strict bool
+}
+
+func (re Reserved) IsFixedSize() (BinarySize, bool) { return re.Size, true }
+
+// Padding skips the bytes required to align the next field,
+// when its offset is only known at runtime.
+// It is not backed by a Go type : its origin is nil.
+type Padding struct {
+	// Align is the alignment, relative to the start of the struct
+	Align BinarySize
+
+	// Strict is true if the bytes must be zero
+	Strict bool
+}
+
+func (Padding) IsFixedSize() (BinarySize, bool) { return 0, false }
+
 // Offset is a fixed size integer pointing to
 // an other type, which has never a fixed size.
 type Offset struct {
@@ -227,18 +612,106 @@ type Slice struct {
 
 	// SubsliceStart is only used for raw data ([]byte).
 	SubsliceStart SubsliceStart
+
+	// BitWidth is not empty for bit-stream arrays, whose elements
+	// are packed (most significant bit first) using a number of bits
+	// only known at runtime.
+	BitWidth FieldExpression
+
+	// ByteWidth is not empty for arrays of unsigned integers or offsets,
+	// whose elements are stored with a number of bytes only known at runtime,
+	// between 1 and the size of the element type (or the offset size).
+	ByteWidth FieldExpression
+
+	// SortedBy is not empty for slices of fixed size
+	// structs sorted by a key
+	SortedBy SortKey
+}
+
+// Map is a map decoded from an array of records,
+// indexed by one of their fields.
+type Map struct {
+	origin types.Type
+
+	// Records is the array of records, which are
+	// the values of the map
+	Records Slice
+
+	// Key is the field of the records used
+	// as map key
+	Key string
+}
+
+// IsFixedSize returns false and the length of the fixed size length prefix, if any.
+func (m Map) IsFixedSize() (BinarySize, bool) { return m.Records.IsFixedSize() }
+
+// String is a Go string decoded from a range of bytes
+type String struct {
+	origin types.Type // may be named, but with underlying string
+
+	Encoding StringEncoding
+
+	// Data is the raw data ([]byte) storing the string,
+	// unused for fixed length strings
+	Data Slice
+
+	// FixedLength is not zero for strings using
+	// a fixed number of bytes, padded with zeros
+	FixedLength BinarySize
+}
+
+// IsFixedSize returns false and the length of the count prefix, if any.
+// Since decoding a string may fail, fixed length strings
+// are not considered as fixed size.
+func (s String) IsFixedSize() (BinarySize, bool) {
+	if s.FixedLength != 0 {
+		return 0, false
+	}
+	return s.Data.IsFixedSize()
+}
+
+// SortKey describes the field (or the range of fields)
+// used to sort the records of a slice.
+type SortKey struct {
+	origin types.Type // of the key fields
+
+	// Start is the field used as key,
+	// or as start of the range
+	Start string
+
+	// End is the field used as (inclusive) end of the range,
+	// or empty
+	End string
+
+	// Check is true if the order of the records
+	// is verified when parsing
+	Check bool
 }
 
+// Origin returns the type of the key
+func (sk SortKey) Origin() types.Type { return sk.origin }
+
+// IsEmpty returns true if the slice is not sorted.
+func (sk SortKey) IsEmpty() bool { return sk.Start == "" }
+
+// IsRange returns true if the records are sorted
+// by (non overlapping) ranges.
+func (sk SortKey) IsRange() bool { return sk.End != "" }
+
 // IsFixedSize returns false and the length of the fixed size length prefix, if any.
 func (sl Slice) IsFixedSize() (BinarySize, bool) {
 	return sl.Count.Size(), false
 }
 
-// IsRawData returns true for []byte
+// IsBitStream returns true for arrays of packed n-bit elements
+func (sl Slice) IsBitStream() bool { return !sl.BitWidth.IsEmpty() }
+
+// IsRawData returns true for []byte (but not for slices of named types)
 func (sl Slice) IsRawData() bool {
-	elem := sl.Elem.Origin().Underlying()
+	elem := sl.Elem.Origin()
 	if basic, isBasic := elem.(*types.Basic); isBasic {
-		return basic.Kind() == types.Byte
+		size, _ := sl.Elem.IsFixedSize()
+		return basic.Kind() == types.Byte && size == Byte
 	}
 	return false
 }
@@ -287,10 +760,15 @@ type UnionTagExplicit struct {
 	FlagField string
 }
 
-// UnionTagImplicit uses a common field and values defined by struct tags
+// UnionTagImplicit uses a common field and values defined by struct tags.
+// The tag field must be found at the same (fixed) position in every member.
 type UnionTagImplicit struct {
 	Tag   Type
 	Flags []constant.Value // in the same order as `Members`
+
+	// Offset is the position of the tag field in every member,
+	// which is zero when the tag is the first field.
+	Offset BinarySize
 }
 
 // Union represents an union of several types,
@@ -306,8 +784,8 @@ type Union struct {
 
 func (Union) IsFixedSize() (BinarySize, bool) { return 0, false }
 
-// isTagImplicit checks for a common tag in each members, which must be the
-// first field, and have same type.
+// isTagImplicit checks for a common tag in each members, which must be
+// found at the same offset (known at compile time), and have same type.
 // If so, it returns the tag [Type]
 func isTagImplicit(members []Struct) (UnionTagImplicit, bool) {
 	out := UnionTagImplicit{
@@ -315,21 +793,42 @@ func isTagImplicit(members []Struct) (UnionTagImplicit, bool) {
 	}
 
 	all := map[types.Type]bool{}
+	offsets := map[BinarySize]bool{}
 	for i, member := range members {
-		if len(member.Fields) == 0 {
+		tagField, offset, ok := member.implicitTag()
+		if !ok {
 			return out, false
 		}
-		firstField := member.Fields[0]
-		all[firstField.Type.Origin()] = true
-		out.Flags[i] = firstField.UnionTag
+		all[tagField.Type.Origin()] = true
+		offsets[offset] = true
+		out.Flags[i] = tagField.UnionTag
+		out.Tag = tagField.Type
+		out.Offset = offset
 	}
-	if len(all) != 1 {
+	if len(all) != 1 || len(offsets) != 1 {
 		return out, false
 	}
-	out.Tag = members[0].Fields[0].Type
 	return out, true
 }
 
+// implicitTag returns the field with a union tag, and its offset,
+// or false if there is no such field, or if the field is not at
+// a fixed offset
+func (st Struct) implicitTag() (Field, BinarySize, bool) {
+	var offset BinarySize
+	for _, field := range st.Fields {
+		size, isFixedSize := field.Type.IsFixedSize()
+		if field.UnionTag != nil {
+			return field, offset, isFixedSize
+		}
+		if !isFixedSize {
+			return Field{}, 0, false
+		}
+		offset += size
+	}
+	return Field{}, 0, false
+}
+
 // Opaque represents a type with no binary structure.
 // The parsing and writting step will be replaced by placeholder methods.
 type Opaque struct {
@@ -344,3 +843,27 @@ type Opaque struct {
 }
 
 func (Opaque) IsFixedSize() (BinarySize, bool) { return 0, false }
+
+// Codec is a type (usually from an other package) whose
+// parsing and writing functions are registered with a
+// package level directive.
+type Codec struct {
+	origin types.Type
+
+	// Name is the name of the type, as used in generated code
+	Name string
+
+	// Parse is the parsing function, as used in generated code,
+	// with signature func([]byte) (T, int, error)
+	Parse string
+
+	// Write is the optional writing function, as used in generated code,
+	// with signature func([]byte, T) []byte
+	Write string
+
+	// Size is the number of bytes used by the type,
+	// or 0 if it is not fixed
+	Size BinarySize
+}
+
+func (co Codec) IsFixedSize() (BinarySize, bool) { return co.Size, co.Size != 0 }
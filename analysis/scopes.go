@@ -9,6 +9,7 @@ type Scope interface {
 
 func (SingleField) isScope()       {}
 func (StaticSizedFields) isScope() {}
+func (OptionalFields) isScope()    {}
 
 type SingleField Field
 
@@ -16,6 +17,11 @@ type SingleField Field
 // Slice and Offset may be used to denote the fixed size part item.
 type StaticSizedFields []Field
 
+// OptionalFields is a list of fields with a static size, where
+// some of them are only present if their [Field.PresentIf] condition is true.
+// As for [StaticSizedFields], Slice and Offset may be used to denote the fixed size part item.
+type OptionalFields []Field
+
 // Size return the cumulated size of all fields
 func (fs StaticSizedFields) Size() BinarySize {
 	var out BinarySize
@@ -32,7 +38,22 @@ func (st Struct) Scopes() (out []Scope) {
 		fixedSize     StaticSizedFields
 		offsetsFields []Scope
 	)
+	// close the current fixedSize array if needed
+	closeFixedSize := func() {
+		if len(fixedSize) != 0 {
+			out = append(out, fixedSizeScope(fixedSize))
+		}
+		out = append(out, offsetsFields...)
+		offsetsFields = nil
+		fixedSize = nil
+	}
 	for _, field := range st.Fields {
+		// the condition of an optional field may only be
+		// evaluated once the previous fields have been read
+		if field.PresentIf.RefersToFields() {
+			closeFixedSize()
+		}
+
 		// append to the static fields
 		if _, isFixedSize := field.Type.IsFixedSize(); isFixedSize {
 			fixedSize = append(fixedSize, field)
@@ -44,33 +65,34 @@ func (st Struct) Scopes() (out []Scope) {
 			fixedSize = append(fixedSize, field)
 			offsetsFields = append(offsetsFields, SingleField(field))
 			continue
-		} else if slice, isSlice := field.Type.(Slice); isSlice && slice.Count.Size() != 0 {
+		} else if hasCountPrefix(field.Type) {
 			fixedSize = append(fixedSize, field)
 			// and also start a new scope
 		}
 
 		// else, close the current fixedSize array ...
-		if len(fixedSize) != 0 {
-			out = append(out, fixedSize)
-			out = append(out, offsetsFields...)
-			offsetsFields = nil
-			fixedSize = nil
-		}
+		closeFixedSize()
 
 		// and add a standalone field
 		out = append(out, SingleField(field))
 	}
 
-	// close the current fixedSize array if needed
-	if len(fixedSize) != 0 {
-		out = append(out, fixedSize)
-	}
-
-	out = append(out, offsetsFields...)
+	closeFixedSize()
 
 	return out
 }
 
+// fixedSizeScope returns [OptionalFields] if at
+// least one field of [fs] is optional
+func fixedSizeScope(fs StaticSizedFields) Scope {
+	for _, field := range fs {
+		if !field.PresentIf.IsEmpty() {
+			return OptionalFields(fs)
+		}
+	}
+	return fs
+}
+
 // Size returns the binary size occupied by the count field,
 // or zero if it is specified externally
 func (c ArrayCount) Size() BinarySize {
@@ -79,6 +101,25 @@ func (c ArrayCount) Size() BinarySize {
 		return Uint16
 	case FirstUint32:
 		return Uint32
+	case FirstUint8:
+		return Byte
+	case FirstUint24:
+		return Uint24
 	}
 	return 0
 }
+
+// hasCountPrefix returns true for slices (and types stored as slices)
+// whose length is written at the start of the array
+func hasCountPrefix(ty Type) bool {
+	switch ty := ty.(type) {
+	case Slice:
+		return ty.Count.Size() != 0
+	case Map:
+		return ty.Records.Count.Size() != 0
+	case String:
+		return ty.FixedLength == 0 && ty.Data.Count.Size() != 0
+	default:
+		return false
+	}
+}
@@ -25,9 +25,83 @@ type parsedTags struct {
 	unionField *types.Var
 	unionTag   constant.Value
 
+	// unwrapFlag is the (bool) field recording that
+	// a union member was found in a wrapper struct
+	unwrapFlag string
+
 	// isCustom is true if the field has
 	// a custom parser/writter
 	isOpaque bool
+
+	// compression is used for compressed data, with
+	// the uncompressed length given by [uncompressedLength]
+	compression        Compression
+	uncompressedLength FieldExpression
+
+	// checksum is non zero for fields storing
+	// the checksum of [checksumStart, checksumStart + checksumLength)
+	checksum                      ChecksumAlgorithm
+	checksumStart, checksumLength FieldExpression
+
+	// encoding selects a non default binary representation
+	encoding string
+
+	// bitWidth is used for arrays of packed n-bit elements
+	bitWidth FieldExpression
+
+	// byteWidth is used for arrays of integers (or offsets)
+	// stored with a number of bytes only known at runtime
+	byteWidth FieldExpression
+
+	// binarySize overrides the representation of integers (or elements of arrays of integers)
+	// as stored in binary files, or is the zero value
+	binarySize binaryInt
+
+	// bits is non nil for fields packed in an integer,
+	// sharing their storage with the neighbouring fields
+	bits *bitRange
+
+	// align is the alignment (in bytes) of the start of the field,
+	// or 0
+	align BinarySize
+
+	// reserved is the number of bytes skipped for a reserved field,
+	// or 0
+	reserved BinarySize
+
+	// expect is not empty for fields with
+	// constant values
+	expect []constant.Value
+
+	// constraints are checked by the generated validate method
+	constraints Constraints
+
+	// sortedBy is the key of a slice of sorted records,
+	// whose order is checked if checkSorted is true
+	sortedBy    string
+	checkSorted bool
+
+	// mapKey is the field of the records used as key
+	// for map fields
+	mapKey string
+
+	// stringLayout selects how the bytes of a string
+	// are delimited
+	stringLayout string
+
+	// isEnum restricts the values of a named integer type
+	// to its declared constants
+	isEnum bool
+
+	// presentIf is not empty for optional fields,
+	// only stored when the expression is non zero
+	presentIf FieldExpression
+}
+
+// bitRange is an inclusive range of bits, where
+// bit 0 is the least significant one
+type bitRange struct {
+	first, last int
 }
 
 func newTags(st *types.Struct, tags reflect.StructTag) (out parsedTags) {
@@ -54,8 +128,16 @@ func newTags(st *types.Struct, tags reflect.StructTag) (out parsedTags) {
 		out.arrayCount = FirstUint16
 	case "FirstUint32":
 		out.arrayCount = FirstUint32
+	case "FirstUint8":
+		out.arrayCount = FirstUint8
+	case "FirstUint24":
+		out.arrayCount = FirstUint24
 	case "ToEnd":
 		out.arrayCount = ToEnd
+	case "FirstUIntBase128":
+		out.arrayCount = FirstUIntBase128
+	case "First255UInt16":
+		out.arrayCount = First255UInt16
 	default:
 		if _, field, hasComputedField := strings.Cut(tag, "ComputedField-"); hasComputedField {
 			out.arrayCount = ComputedField
@@ -74,6 +156,8 @@ func newTags(st *types.Struct, tags reflect.StructTag) (out parsedTags) {
 	switch tag := tags.Get("offsetSize"); tag {
 	case "Offset16":
 		out.offsetSize = Offset16
+	case "Offset24":
+		out.offsetSize = Offset24
 	case "Offset32":
 		out.offsetSize = Offset32
 	case "":
@@ -84,6 +168,8 @@ func newTags(st *types.Struct, tags reflect.StructTag) (out parsedTags) {
 	switch tag := tags.Get("offsetsArray"); tag {
 	case "Offset16":
 		out.offsetsArray = Offset16
+	case "Offset24":
+		out.offsetsArray = Offset24
 	case "Offset32":
 		out.offsetsArray = Offset32
 	case "":
@@ -114,12 +200,132 @@ func newTags(st *types.Struct, tags reflect.StructTag) (out parsedTags) {
 		}
 	}
 
+	if unwrapFlag := tags.Get("unwrapFlag"); unwrapFlag != "" {
+		var flag *types.Var
+		for i := 0; i < st.NumFields(); i++ {
+			if fi := st.Field(i); fi.Name() == unwrapFlag {
+				flag = fi
+				break
+			}
+		}
+		if flag == nil {
+			panic("unknow field for unwrapFlag: " + unwrapFlag)
+		}
+		if basic, isBasic := flag.Type().Underlying().(*types.Basic); !isBasic || basic.Kind() != types.Bool {
+			panic("unwrapFlag field must be a bool: " + unwrapFlag)
+		}
+		out.unwrapFlag = unwrapFlag
+	}
+
 	if unionTag := tags.Get("unionTag"); unionTag != "" {
-		value, err := strconv.Atoi(unionTag)
+		value, err := strconv.ParseInt(unionTag, 0, 64)
 		if err != nil {
 			panic(err)
 		}
-		out.unionTag = constant.MakeInt64(int64(value))
+		out.unionTag = constant.MakeInt64(value)
+	}
+
+	if expect := tags.Get("expect"); expect != "" {
+		for _, value := range strings.Split(expect, "|") {
+			out.expect = append(out.expect, parseIntConstant(strings.TrimSpace(value)))
+		}
+	}
+
+	if min := tags.Get("min"); min != "" {
+		out.constraints.Min = parseIntConstant(min)
+	}
+	if max := tags.Get("max"); max != "" {
+		out.constraints.Max = parseIntConstant(max)
+	}
+	if oneOf := tags.Get("oneOf"); oneOf != "" {
+		for _, value := range strings.Split(oneOf, "|") {
+			out.constraints.OneOf = append(out.constraints.OneOf, parseIntConstant(strings.TrimSpace(value)))
+		}
+	}
+	_, out.constraints.NonZero = tags.Lookup("nonZero")
+	if lessThan := tags.Get("lessThan"); lessThan != "" {
+		out.constraints.LessThan = newFieldExpression(lessThan, st)
+	}
+
+	out.mapKey = tags.Get("mapKey")
+	out.stringLayout = tags.Get("stringLayout")
+	out.sortedBy = tags.Get("sortedBy")
+	_, out.isEnum = tags.Lookup("isEnum")
+	if presentIf := tags.Get("presentIf"); presentIf != "" {
+		out.presentIf = newFieldExpression(presentIf, st)
+	}
+
+	_, out.checkSorted = tags.Lookup("checkSorted")
+	if out.checkSorted && out.sortedBy == "" {
+		panic("checkSorted requires a sortedBy tag")
+	}
+
+	out.encoding = tags.Get("encoding")
+
+	switch tag := tags.Get("checksum"); tag {
+	case "OpenType":
+		out.checksum = OpenTypeChecksum
+	case "CRC32":
+		out.checksum = CRC32
+	case "Adler32":
+		out.checksum = Adler32
+	case "":
+	default:
+		panic("invalid tag for checksum: " + tag)
+	}
+	switch tag := tags.Get("checksumRange"); tag {
+	case "Table", "":
+	default:
+		start, length, ok := strings.Cut(tag, ",")
+		if !ok {
+			panic("expected <start>,<length> for checksumRange, got " + tag)
+		}
+		out.checksumStart = newFieldExpression(strings.TrimSpace(start), st)
+		out.checksumLength = newFieldExpression(strings.TrimSpace(length), st)
+	}
+
+	switch tag := tags.Get("compression"); tag {
+	case "zlib":
+		out.compression = Zlib
+	case "deflate":
+		out.compression = Deflate
+	case "gzip":
+		out.compression = Gzip
+	case "":
+	default:
+		panic("invalid tag for compression: " + tag)
+	}
+	if length := tags.Get("uncompressedLength"); length != "" {
+		out.uncompressedLength = newFieldExpression(length, st)
+	}
+	if (out.compression == NoCompression) != out.uncompressedLength.IsEmpty() {
+		panic("compression and uncompressedLength tags must be provided together")
+	}
+
+	if bitWidth := tags.Get("bitWidth"); bitWidth != "" {
+		out.bitWidth = newFieldExpression(bitWidth, st)
+	}
+
+	if size := tags.Get("binarySize"); size != "" {
+		out.binarySize = parseBinarySize(size)
+	}
+
+	if byteWidth := tags.Get("byteWidth"); byteWidth != "" {
+		if !out.bitWidth.IsEmpty() {
+			panic("bitWidth and byteWidth tags are exclusive")
+		}
+		out.byteWidth = newFieldExpression(byteWidth, st)
+	}
+
+	if bits, ok := tags.Lookup("bits"); ok {
+		out.bits = parseBitRange(bits)
+	}
+
+	if align := tags.Get("align"); align != "" {
+		out.align = parsePositiveSize("align", align)
+	}
+	if reserved := tags.Get("reserved"); reserved != "" {
+		out.reserved = parsePositiveSize("reserved", reserved)
 	}
 
 	if args := tags.Get("arguments"); args != "" {
@@ -140,6 +346,169 @@ func newTags(st *types.Struct, tags reflect.StructTag) (out parsedTags) {
 	return out
 }
 
+// parseBitRange accepts <first>-<last> or <bit>
+func parseBitRange(tag string) *bitRange {
+	firstS, lastS, isRange := strings.Cut(tag, "-")
+	if !isRange {
+		lastS = firstS
+	}
+	first, err := strconv.Atoi(strings.TrimSpace(firstS))
+	if err != nil {
+		panic("invalid tag for bits: " + tag)
+	}
+	last, err := strconv.Atoi(strings.TrimSpace(lastS))
+	if err != nil {
+		panic("invalid tag for bits: " + tag)
+	}
+	if first < 0 || last < first {
+		panic("invalid range for bits: " + tag)
+	}
+	return &bitRange{first: first, last: last}
+}
+
+// parseIntConstant accepts decimal, hexadecimal (0x) and octal (0o) integers
+func parseIntConstant(tag string) constant.Value {
+	if value, err := strconv.ParseInt(tag, 0, 64); err == nil {
+		return constant.MakeInt64(value)
+	}
+	value, err := strconv.ParseUint(tag, 0, 64)
+	if err != nil {
+		panic("invalid integer constant: " + tag)
+	}
+	return constant.MakeUint64(value)
+}
+
+// binaryInt is the representation of an integer in binary files
+type binaryInt struct {
+	size   BinarySize
+	signed bool // two's complement
+}
+
+// parseBinarySize parses the representation of an integer, given
+// as a type name (like uint16 or int24) or as a number of bytes (unsigned)
+func parseBinarySize(tag string) binaryInt {
+	var out binaryInt
+	switch tag {
+	case "uint8", "int8":
+		out.size = Byte
+	case "uint16", "int16":
+		out.size = Uint16
+	case "uint24", "int24":
+		out.size = Uint24
+	case "uint32", "int32":
+		out.size = Uint32
+	case "uint64", "int64":
+		out.size = Uint64
+	default:
+		out.size = parsePositiveSize("binarySize", tag)
+		if out.size != Byte && out.size != Uint16 && out.size != Uint24 && out.size != Uint32 && out.size != Uint64 {
+			panic("invalid tag for binarySize: " + tag)
+		}
+		return out
+	}
+	out.signed = strings.HasPrefix(tag, "int")
+	return out
+}
+
+func parsePositiveSize(tagName, tag string) BinarySize {
+	size, err := strconv.Atoi(tag)
+	if err != nil || size <= 0 {
+		panic("invalid tag for " + tagName + ": " + tag)
+	}
+	return BinarySize(size)
+}
+
+// StringEncoding is the encoding of the bytes of a string
+type StringEncoding uint8
+
+const (
+	UTF8 StringEncoding = iota
+	ASCII
+	UTF16BE
+	MacRoman
+)
+
+func newStringEncoding(tag string) (StringEncoding, bool) {
+	switch tag {
+	case "UTF-8", "":
+		return UTF8, true
+	case "ASCII":
+		return ASCII, true
+	case "UTF-16BE":
+		return UTF16BE, true
+	case "MacRoman":
+		return MacRoman, true
+	default:
+		return 0, false
+	}
+}
+
+// FloatEncoding is the binary representation of float numbers
+type FloatEncoding uint8
+
+const (
+	// IEEE754 is the default encoding, using 4 bytes for float32
+	// and 8 bytes for float64
+	IEEE754 FloatEncoding = iota
+	// F2Dot14 is a signed 2.14 fixed point number, stored in 2 bytes
+	F2Dot14
+	// Fixed is a signed 16.16 fixed point number, stored in 4 bytes
+	Fixed
+)
+
+func newFloatEncoding(tag string) (FloatEncoding, bool) {
+	switch tag {
+	case "IEEE", "":
+		return IEEE754, true
+	case "F2Dot14":
+		return F2Dot14, true
+	case "Fixed":
+		return Fixed, true
+	default:
+		return 0, false
+	}
+}
+
+// VarIntEncoding is a variable-length encoding of integers
+type VarIntEncoding uint8
+
+const (
+	_ VarIntEncoding = iota
+	// UIntBase128 is the WOFF2 encoding of uint32, using 1 to 5 bytes
+	UIntBase128
+	// U255UInt16 is the WOFF2 encoding of uint16, using 1 to 3 bytes
+	U255UInt16
+	// CFFOperand is the encoding of the operands in CFF DICTs,
+	// either integers or real numbers
+	CFFOperand
+)
+
+func newVarIntEncoding(tag string) (VarIntEncoding, bool) {
+	switch tag {
+	case "UIntBase128":
+		return UIntBase128, true
+	case "255UInt16":
+		return U255UInt16, true
+	case "CFFOperand":
+		return CFFOperand, true
+	default:
+		return 0, false
+	}
+}
+
+func (enc VarIntEncoding) String() string {
+	switch enc {
+	case UIntBase128:
+		return "UIntBase128"
+	case U255UInt16:
+		return "255UInt16"
+	case CFFOperand:
+		return "CFFOperand"
+	default:
+		return ""
+	}
+}
+
 type Argument struct {
 	VariableName string
 	TypeName     string
@@ -149,6 +518,14 @@ type commments struct {
 	// externalArguments may be provided it the type parsing/writting function
 	// requires data not provided in the input slice
 	externalArguments []Argument
+
+	// strict is true if the reserved bytes
+	// must be checked to be zero
+	strict bool
+
+	// unwrap is true for wrapper structs, whose
+	// inner value is stored in place of the wrapper
+	unwrap bool
 }
 
 // parse the type documentation looking for special comments
@@ -162,6 +539,10 @@ func parseComments(doc *ast.CommentGroup) (out commments) {
 			if _, argDef, ok := strings.Cut(value, "argument="); ok {
 				name, typeN, _ := strings.Cut(argDef, " ")
 				out.externalArguments = append(out.externalArguments, Argument{VariableName: name, TypeName: typeN})
+			} else if strings.TrimSpace(value) == "strict" {
+				out.strict = true
+			} else if strings.TrimSpace(value) == "unwrap" {
+				out.unwrap = true
 			}
 		}
 	}
@@ -194,6 +575,17 @@ const (
 	// given by an other field, parsed previously,
 	// or computed by a method or an expression
 	ToComputedField
+
+	// The length is written at the start of the array, as an UIntBase128
+	FirstUIntBase128
+	// The length is written at the start of the array, as a 255UInt16
+	First255UInt16
+
+	// The length is written at the start of the array, as an uint8,
+	// as in Pascal strings
+	FirstUint8
+	// The length is written at the start of the array, as an uint24
+	FirstUint24
 )
 
 // SubsliceStart indicates where the start of the subslice
@@ -217,6 +609,8 @@ const (
 	Offset16
 	// The offset is written as uint32
 	Offset32
+	// The offset is written as uint24, as in COLR version 1
+	Offset24
 )
 
 func (os OffsetSize) binary() BinarySize {
@@ -225,11 +619,40 @@ func (os OffsetSize) binary() BinarySize {
 		return Uint16
 	case Offset32:
 		return Uint32
+	case Offset24:
+		return Uint24
 	default:
 		return 0
 	}
 }
 
+// Compression is the algorithm used to compress
+// a field data
+type Compression uint8
+
+const (
+	NoCompression Compression = iota
+	// zlib format (RFC 1950), as used in WOFF 1.0
+	Zlib
+	// raw deflate format (RFC 1951)
+	Deflate
+	// gzip format (RFC 1952), as used for SVG documents
+	Gzip
+)
+
+// ChecksumAlgorithm is the function used to compute a checksum
+type ChecksumAlgorithm uint8
+
+const (
+	NoChecksum ChecksumAlgorithm = iota
+	// OpenTypeChecksum is the sum of the data, read as uint32
+	// (padded with zeros)
+	OpenTypeChecksum
+	// CRC32 uses the IEEE polynomial
+	CRC32
+	Adler32
+)
+
 // OffsetRelative indicates if the offset is related
 // to the current slice or the one of its parent type (or grand parent)
 type OffsetRelative uint8
@@ -57,6 +57,9 @@ func main() {
 		}
 
 		ana := analysis.NewAnalyserFromPkg(pkg, path, absPath)
+		for _, diagnostic := range ana.Diagnostics {
+			fmt.Println("Warning:", diagnostic)
+		}
 
 		buf := generator.NewBuffer(accu)
 		parser.ParsersForFile(ana, &buf)
@@ -32,6 +32,17 @@ func (db *Buffer) Add(decl Declaration) {
 	db.seen[decl.ID] = true
 }
 
+// IsUsed returns true if one of the declarations
+// already added refers to [id]
+func (db Buffer) IsUsed(id string) bool {
+	for _, decl := range db.decls {
+		if decl.ID != id && strings.Contains(decl.Content, id) {
+			return true
+		}
+	}
+	return false
+}
+
 // remove non exported, unused function declaration
 func (db Buffer) filterUnused(childTypes map[*types.Named]bool) []Declaration {
 	var filtered []Declaration
@@ -79,11 +90,30 @@ type Declaration struct {
 // Name returns the representation of the given type in generated code,
 // either its local name or its String
 func Name(ty analysis.Type) string {
-	if named, isNamed := ty.Origin().(*types.Named); isNamed {
-		return named.Obj().Name()
+	if co, isCodec := ty.(analysis.Codec); isCodec {
+		return co.Name
 	}
+	return TypeName(ty.Origin())
+}
 
-	return ty.Origin().String()
+// localPackage is the package of the generated code,
+// see [SetLocalPackage]
+var localPackage *types.Package
+
+// SetLocalPackage must be called with the package of the generated code
+// before using [TypeName], so that the types of other packages are qualified.
+func SetLocalPackage(pkg *types.Package) { localPackage = pkg }
+
+// TypeName is the same as [Name], but for a Go type.
+// The types (including the components of composite types)
+// of packages other than [localPackage] are qualified.
+func TypeName(ty types.Type) string {
+	return types.TypeString(ty, func(pkg *types.Package) string {
+		if pkg == localPackage {
+			return ""
+		}
+		return pkg.Name()
+	})
 }
 
 // Expression is a Go expression, such as a variable name, a static number, or an expression
@@ -121,7 +151,7 @@ type Err interface {
 type ErrVariable string
 
 func (ev ErrVariable) wrap(context string) string {
-	return fmt.Sprintf(`fmt.Errorf("reading %s: %%s", %s)`, context, ev)
+	return fmt.Sprintf(`fmt.Errorf("reading %s: %%w", %s)`, context, ev)
 }
 
 // represent a fmt.Errorf(..., args) statement
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// readUintAt returns the expression reading an unsigned
+// integer of [size] bytes, as uint64 for sizes greater than 4
+func readUintAt(slice, offset gen.Expression, size an.BinarySize) gen.Expression {
+	switch size {
+	case 1:
+		return fmt.Sprintf("%s[%s]", slice, offset)
+	case 2:
+		return fmt.Sprintf("binary.BigEndian.Uint16(%s[%s:])", slice, offset)
+	case 4:
+		return fmt.Sprintf("binary.BigEndian.Uint32(%s[%s:])", slice, offset)
+	case 8:
+		return fmt.Sprintf("binary.BigEndian.Uint64(%s[%s:])", slice, offset)
+	}
+	typ := readUintType(size)
+	bytes := make([]string, size)
+	for i := range bytes {
+		b := fmt.Sprintf("%s(%s[%s])", typ, slice, gen.ArrayOffset(offset, fmt.Sprint(i), 1))
+		if shift := 8 * (int(size) - 1 - i); shift != 0 {
+			b = fmt.Sprintf("%s<<%d", b, shift)
+		}
+		bytes[i] = b
+	}
+	return strings.Join(bytes, " | ")
+}
+
+// readUintType returns the type of the expression
+// returned by [readUintAt]
+func readUintType(size an.BinarySize) string {
+	switch {
+	case size == 1:
+		return "byte"
+	case size <= 2:
+		return "uint16"
+	case size <= 4:
+		return "uint32"
+	default:
+		return "uint64"
+	}
+}
+
+// byteWidthDefinition defines the "width" variable and checks
+// it is between 1 and [maxSize], unless the array is empty
+// (as for an empty CFF INDEX, which has no offSize)
+func byteWidthDefinition(sl an.Slice, cc gen.Context, count gen.Expression, maxSize an.BinarySize) string {
+	errWidth := cc.ErrReturn(gen.ErrFormated(`"invalid byte width %d", width`))
+	return fmt.Sprintf(`width := int(%s)
+		if %s != 0 && (width < 1 || width > %d) {
+			%s
+		}`, sl.ByteWidth.Code(cc.ObjectVar), count, maxSize, errWidth)
+}
+
+// widthSwitch returns the statement assigning the unsigned integer
+// of "width" bytes at [offset] to [target], after conversion to [typeName].
+func widthSwitch(cc gen.Context, offset gen.Expression, maxSize an.BinarySize, target, typeName string) string {
+	cases := make([]string, maxSize)
+	for i := range cases {
+		size := an.BinarySize(i + 1)
+		value := readUintAt(cc.Slice, offset, size)
+		if typeName != readUintType(size) {
+			value = fmt.Sprintf("%s(%s)", typeName, value)
+		}
+		cases[i] = fmt.Sprintf(`case %d:
+			%s = %s`, size, target, value)
+	}
+	return fmt.Sprintf(`switch width {
+		%s
+	}`, strings.Join(cases, "\n"))
+}
+
+// The field is a slice of unsigned integers, each of them using
+// a number of bytes only known at runtime.
+// The generated code will look like
+//
+//	width := int(<expr>)
+//	if arrayLength != 0 && (width < 1 || width > 4) {
+//		return err
+//	}
+//	if len(data) < n + arrayLength*width {
+//		return err
+//	}
+//	out = make([]uint32, arrayLength)
+//	for i := range out {
+//		switch width {
+//		case 1:
+//			out[i] = uint32(data[n+i*width])
+//		...
+//		}
+//	}
+//	n += arrayLength*width
+func parserForSliceByteWidth(sl an.Slice, cc *gen.Context, count gen.Expression, target string) string {
+	elemName := gen.Name(sl.Elem)
+	elemSize, _ := sl.Elem.IsFixedSize()
+	byteLength := count + "*width"
+
+	out := fmt.Sprintf(`%s
+		%s
+		%s = make([]%s, %s) // allocation guarded by the previous check
+		for i := range %s {
+			%s
+		}
+		`, byteWidthDefinition(sl, *cc, count, elemSize),
+		lengthCheck(*cc, cc.Offset.WithAffine(byteLength, 1)),
+		target, elemName, count,
+		target,
+		widthSwitch(*cc, cc.Offset.WithAffine("i*width", 1), elemSize, target+"[i]", elemName),
+	)
+	if cc.IgnoreUpdateOffset {
+		return out
+	}
+	return out + cc.Offset.UpdateStatementDynamic(byteLength)
+}
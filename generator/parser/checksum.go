@@ -0,0 +1,207 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// checksums are not checked during parsing, but with a separated,
+// generated method : verifyChecksums(src []byte) error
+
+// checksumHelpers are sorted so that a declaration is listed
+// before the declarations it depends on
+var checksumHelpers = []gen.Declaration{
+	{
+		ID: "checksumOpenTypeZeroed",
+		Content: `// checksumOpenTypeZeroed returns the checksum of [data],
+		// as if the 4 bytes at [field] were zero.
+		func checksumOpenTypeZeroed(data []byte, field int) uint32 {
+			sum := checksumOpenType(data)
+			for i := field; i < field+4; i++ {
+				sum -= uint32(data[i]) << (8 * (3 - i%4))
+			}
+			return sum
+		}
+		`,
+	},
+	{
+		ID: "checksumCRC32Zeroed",
+		Content: `// checksumZeroWord is used in place of a checksum field
+		var checksumZeroWord [4]byte
+
+		// checksumCRC32Zeroed returns the CRC32 checksum of [data],
+		// as if the 4 bytes at [field] were zero.
+		func checksumCRC32Zeroed(data []byte, field int) uint32 {
+			sum := crc32.ChecksumIEEE(data[:field])
+			sum = crc32.Update(sum, crc32.IEEETable, checksumZeroWord[:])
+			return crc32.Update(sum, crc32.IEEETable, data[field+4:])
+		}
+		`,
+	},
+	{
+		ID: "checksumAdler32Zeroed",
+		Content: `// checksumAdler32Zeroed returns the Adler32 checksum of [data],
+		// as if the 4 bytes at [field] were zero.
+		func checksumAdler32Zeroed(data []byte, field int) uint32 {
+			const (
+				mod  = 65521
+				nmax = 5552 // the sums do not overflow for nmax bytes
+			)
+			a, b := uint32(1), uint32(0)
+			for i, c := range data {
+				if field <= i && i < field+4 {
+					c = 0
+				}
+				a += uint32(c)
+				b += a
+				if i%nmax == nmax-1 {
+					a, b = a%mod, b%mod
+				}
+			}
+			return (b%mod)<<16 | a%mod
+		}
+		`,
+	},
+	{
+		ID: "checksumOpenType",
+		Content: `// checksumOpenType returns the sum of [data] read as uint32,
+		// padded with zeros if needed.
+		func checksumOpenType(data []byte) uint32 {
+			var sum uint32
+			for ; len(data) >= 4; data = data[4:] {
+				sum += binary.BigEndian.Uint32(data)
+			}
+			if len(data) != 0 {
+				var tail [4]byte
+				copy(tail[:], data)
+				sum += binary.BigEndian.Uint32(tail[:])
+			}
+			return sum
+		}
+		`,
+	},
+	{
+		ID: "checksumError",
+		Content: `// checksumError is returned when a checksum does not
+		// match its data
+		type checksumError struct {
+			field    string
+			expected uint32 // as stored in the field
+			got      uint32 // as computed from the data
+		}
+
+		func (err checksumError) Error() string {
+			return fmt.Sprintf("invalid checksum for %s: expected 0x%08x, got 0x%08x", err.field, err.expected, err.got)
+		}
+		`,
+	},
+}
+
+// checksumCall returns the expression computing the checksum of [data]
+func checksumCall(algorithm an.ChecksumAlgorithm, data gen.Expression) gen.Expression {
+	switch algorithm {
+	case an.OpenTypeChecksum:
+		return fmt.Sprintf("checksumOpenType(%s)", data)
+	case an.CRC32:
+		return fmt.Sprintf("crc32.ChecksumIEEE(%s)", data)
+	case an.Adler32:
+		return fmt.Sprintf("adler32.Checksum(%s)", data)
+	default:
+		panic("exhaustive switch")
+	}
+}
+
+// checksumZeroedCall returns the expression computing the checksum of [data],
+// where the checksum field at [field] is considered to be zero, without copying [data]
+func checksumZeroedCall(algorithm an.ChecksumAlgorithm, data gen.Expression, field int) gen.Expression {
+	switch algorithm {
+	case an.OpenTypeChecksum:
+		return fmt.Sprintf("checksumOpenTypeZeroed(%s, %d)", data, field)
+	case an.CRC32:
+		return fmt.Sprintf("checksumCRC32Zeroed(%s, %d)", data, field)
+	case an.Adler32:
+		return fmt.Sprintf("checksumAdler32Zeroed(%s, %d)", data, field)
+	default:
+		panic("exhaustive switch")
+	}
+}
+
+func computeChecksumName(fieldName string) string {
+	return "compute" + strings.Title(fieldName)
+}
+
+// checksumsForTable returns the methods computing and verifying the checksums
+// of [ta], or nil if [ta] has no checksum field.
+func checksumsForTable(ta an.Struct) []gen.Declaration {
+	origin := ta.Origin().(*types.Named)
+	typeName := origin.Obj().Name()
+	const objectVar = "item"
+
+	var (
+		out    []gen.Declaration
+		checks []string
+	)
+	for _, field := range ta.Fields {
+		ch := field.Checksum
+		if ch.Algorithm == an.NoChecksum {
+			continue
+		}
+
+		methodName := computeChecksumName(field.Name)
+		var body string
+		if ch.IsTable() {
+			body = fmt.Sprintf(`// the checksum field itself is considered to be zero
+			if len(src) < %d {
+				return 0, fmt.Errorf("EOF: expected length: %d, got %%d", len(src))
+			}
+			return %s, nil`, ch.Offset+4, ch.Offset+4, checksumZeroedCall(ch.Algorithm, "src", int(ch.Offset)))
+		} else {
+			body = fmt.Sprintf(`start, length := int(%s), int(%s)
+			if start < 0 || length < 0 || len(src) < start+length {
+				return 0, fmt.Errorf("invalid range for %s: [%%d, %%d+%%d), for length %%d", start, start, length, len(src))
+			}
+			return %s, nil`, ch.Start.Code(objectVar), ch.Length.Code(objectVar), field.Name,
+				checksumCall(ch.Algorithm, "src[start:start+length]"))
+		}
+
+		out = append(out, gen.Declaration{
+			ID:     typeName + "." + methodName,
+			Origin: origin,
+			Content: fmt.Sprintf(`// %s returns the checksum of the data described by [%s],
+			// using [src] to resolve the range.
+			func (%s *%s) %s(src []byte) (uint32, error) {
+				%s
+			}
+			`, methodName, field.Name, objectVar, typeName, methodName, body),
+		})
+
+		checks = append(checks, fmt.Sprintf(`if got, err := %s.%s(src); err != nil {
+				return err
+			} else if got != uint32(%s) {
+				return checksumError{field: "%s.%s", expected: uint32(%s), got: got}
+			}`, objectVar, methodName, fmt.Sprintf("%s.%s", objectVar, field.Name),
+			typeName, field.Name, fmt.Sprintf("%s.%s", objectVar, field.Name)))
+	}
+
+	if len(checks) == 0 {
+		return nil
+	}
+
+	out = append(out, gen.Declaration{
+		ID:     typeName + ".verifyChecksums",
+		Origin: origin,
+		Content: fmt.Sprintf(`// verifyChecksums checks that the fields storing a checksum
+		// match their data, using [src] to resolve the ranges.
+		// It is not called by the parsing function.
+		func (%s *%s) verifyChecksums(src []byte) error {
+			%s
+			return nil
+		}
+		`, objectVar, typeName, strings.Join(checks, "\n")),
+	})
+	return out
+}
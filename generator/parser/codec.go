@@ -0,0 +1,31 @@
+package parser
+
+import (
+	"fmt"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// types with a registered codec are parsed by calling the codec
+// function, with the same signature as the generated parsing functions.
+// When the codec has a fixed size, the values are read once their length
+// is checked, see [parserChecked]
+
+// parseFunction returns the name of the function parsing [ty]
+func parseFunction(ty an.Type) string {
+	if co, isCodec := ty.(an.Codec); isCodec {
+		return co.Parse
+	}
+	return gen.ParseFunctionName(gen.Name(ty))
+}
+
+func parserCodecChecked(co an.Codec, cc gen.Context, target string) string {
+	value := valueName(target)
+	return fmt.Sprintf(`%s, _, err := %s(%s[%s:])
+		if err != nil {
+			%s
+		}
+		%s = %s`, value, co.Parse, cc.Slice, cc.Offset.Value(),
+		cc.ErrReturn(invalidValueError(target)), target, value)
+}
@@ -0,0 +1,165 @@
+package parser
+
+import (
+	"fmt"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// compressed data are inflated by helper functions,
+// which are only added to the output when used
+
+// compressionHelpers are sorted so that a declaration is listed
+// before the declarations it depends on
+var compressionHelpers = []gen.Declaration{
+	{
+		ID: "inflateZlib",
+		Content: `// inflateZlib decompresses the zlib stream at the start of [src], which
+		// must have [length] bytes once uncompressed.
+		// It returns the number of compressed bytes read.
+		func inflateZlib(src []byte, length int) ([]byte, int, error) {
+			r := bytes.NewReader(src)
+			zr, err := zlib.NewReader(r)
+			if err != nil {
+				return nil, 0, err
+			}
+			out, err := readInflated(zr, length, "zlib")
+			if err != nil {
+				return nil, 0, err
+			}
+			return out, len(src) - r.Len(), nil
+		}
+		`,
+	},
+	{
+		ID: "inflateDeflate",
+		Content: `// inflateDeflate decompresses the deflate stream at the start of [src], which
+		// must have [length] bytes once uncompressed.
+		// It returns the number of compressed bytes read.
+		func inflateDeflate(src []byte, length int) ([]byte, int, error) {
+			r := bytes.NewReader(src)
+			out, err := readInflated(flate.NewReader(r), length, "deflate")
+			if err != nil {
+				return nil, 0, err
+			}
+			return out, len(src) - r.Len(), nil
+		}
+		`,
+	},
+	{
+		ID: "inflateGzip",
+		Content: `// inflateGzip decompresses the gzip stream at the start of [src], which
+		// must have [length] bytes once uncompressed.
+		// It returns the number of compressed bytes read.
+		func inflateGzip(src []byte, length int) ([]byte, int, error) {
+			r := bytes.NewReader(src)
+			zr, err := gzip.NewReader(r)
+			if err != nil {
+				return nil, 0, err
+			}
+			zr.Multistream(false)
+			out, err := readInflated(zr, length, "gzip")
+			if err != nil {
+				return nil, 0, err
+			}
+			return out, len(src) - r.Len(), nil
+		}
+		`,
+	},
+	{
+		ID: "readInflated",
+		Content: `// readInflated reads the whole content of [r], which must be exactly [length].
+		// The memory used is bounded by the actual content and [length].
+		func readInflated(r io.Reader, length int, algorithm string) ([]byte, error) {
+			if length < 0 {
+				return nil, compressionError{algorithm: algorithm, expected: length}
+			}
+			var buf bytes.Buffer
+			// read one more byte to detect data exceeding the expected length
+			_, err := io.Copy(&buf, io.LimitReader(r, int64(length)+1))
+			if err != nil {
+				return nil, err
+			}
+			if buf.Len() != length {
+				return nil, compressionError{algorithm: algorithm, expected: length, exceeded: buf.Len() > length}
+			}
+			return buf.Bytes(), nil
+		}
+		`,
+	},
+	{
+		ID: "compressionError",
+		Content: `// compressionError is returned when the uncompressed data
+		// does not match the expected length
+		type compressionError struct {
+			algorithm string
+			expected  int
+			exceeded  bool
+		}
+
+		func (err compressionError) Error() string {
+			if err.exceeded {
+				return fmt.Sprintf("%s data exceeds the expected length %d", err.algorithm, err.expected)
+			}
+			return fmt.Sprintf("%s data does not match the expected length %d", err.algorithm, err.expected)
+		}
+		`,
+	},
+}
+
+func inflateFunction(algorithm an.Compression) string {
+	switch algorithm {
+	case an.Zlib:
+		return "inflateZlib"
+	case an.Deflate:
+		return "inflateDeflate"
+	case an.Gzip:
+		return "inflateGzip"
+	default:
+		panic("exhaustive switch")
+	}
+}
+
+// parserForCompressed inflates the data, and then
+// parses the target from the uncompressed bytes.
+// The generated code will look like
+//
+//	inflated, read, err := inflateZlib(src[n:], int(item.length))
+//	if err != nil {
+//		return err
+//	}
+//	item.data = inflated // or item.data, _, err = parseData(inflated)
+//	n += read
+func parserForCompressed(field an.Field, cc *gen.Context) string {
+	co := field.Type.(an.Compressed)
+	target := cc.Selector(field.Name)
+
+	code := fmt.Sprintf(`inflated, read, err := %s(%s[%s:], int(%s))
+		if err != nil {
+			%s
+		}
+		`, inflateFunction(co.Algorithm), cc.Slice, cc.Offset.Value(), co.UncompressedLength.Code(cc.ObjectVar),
+		cc.ErrReturn(gen.ErrVariable("err")),
+	)
+
+	if _, isStruct := co.Target.(an.Struct); isStruct {
+		// parse from the start of the uncompressed data
+		inflatedContext := *cc
+		inflatedContext.Slice = "inflated"
+		inflatedContext.Offset = gen.NewOffset("inflatedN", 0)
+		inflatedContext.IgnoreUpdateOffset = true
+		targetField := field
+		targetField.Type = co.Target
+		code += fmt.Sprintf(`{
+			%s}
+			`, parserForStructTo(targetField, &inflatedContext, target))
+	} else { // raw data
+		code += fmt.Sprintf("%s = inflated\n", target)
+	}
+
+	if cc.IgnoreUpdateOffset {
+		return code + "_ = read"
+	}
+	return code + cc.Offset.UpdateStatementDynamic("read")
+}
@@ -0,0 +1,137 @@
+package parser
+
+import (
+	"fmt"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// strict bools, enums and validated scalars are checked by the parsing function,
+// once the scope they belong to has been read, reporting
+// an [expectError] (or the error returned by Validate)
+
+// valueChecks returns the checks for the strict bools,
+// enums and validated scalars in [fs], or an empty string.
+// The fields must have been parsed by the caller.
+func valueChecks(fs an.StaticSizedFields, cc gen.Context) string {
+	var code []string
+	for _, field := range fs {
+		fieldSize, _ := field.Type.IsFixedSize()
+		switch ty := field.Type.(type) {
+		case an.Basic:
+			if an.HasValueChecks(ty) {
+				code = append(code, valueCheck(ty, cc, cc.Selector(field.Name), field.Name))
+			}
+		case an.Array:
+			if ba, isBasic := ty.Elem.(an.Basic); isBasic && an.HasValueChecks(ba) {
+				elementSize, _ := ba.IsFixedSize()
+				elemContext := cc
+				elemContext.Offset = gen.NewOffsetDynamic(cc.Offset.WithAffine("i", elementSize))
+				code = append(code, fmt.Sprintf(`for i := range %s {
+					%s
+				}`, cc.Selector(field.Name), valueCheck(ba, elemContext, cc.Selector(field.Name)+"[i]", field.Name)))
+			}
+		}
+		cc.Offset.Increment(fieldSize)
+	}
+	return strings.Join(code, "\n")
+}
+
+// valueCheck returns the check for the strict bool, enum or
+// validated scalar [target], stored at the current offset.
+func valueCheck(ba an.Basic, cc gen.Context, target, fieldName string) string {
+	if ba.HasValidate {
+		return fmt.Sprintf(`if err := %s.Validate(); err != nil {
+			%s
+		}`, target, cc.ErrReturn(invalidValueError(target)))
+	}
+	if ba.IsBool() { // check the stored byte, not the bool
+		errVariable := fmt.Sprintf(`expectError{field: "%s.%s", expected: "0x0 or 0x1", got: b}`, cc.Type, fieldName)
+		return fmt.Sprintf(`if b := %s; b > 1 {
+			%s
+		}`, readBasicTypeAt(cc, an.Byte), cc.ErrReturn(gen.ErrVariable(errVariable)))
+	}
+	return enumCheck(ba, target, cc, fieldName)
+}
+
+// enumConstants returns the names of the constants of [ba],
+// ignoring the duplicated values
+func enumConstants(ba an.Basic) []string {
+	var names []string
+	for i, cst := range ba.Enum {
+		if i != 0 && constant.Compare(cst.Val(), token.EQL, ba.Enum[i-1].Val()) {
+			continue
+		}
+		names = append(names, cst.Name())
+	}
+	return names
+}
+
+func enumCheck(ba an.Basic, target string, cc gen.Context, fieldName string) string {
+	names := enumConstants(ba)
+	errVariable := fmt.Sprintf(`expectError{field: "%s.%s", expected: "%s", got: %s}`,
+		cc.Type, fieldName, strings.Join(names, " or "), rawValue(ba, target))
+	return fmt.Sprintf(`switch %s {
+		case %s:
+		default:
+			%s
+		}`, target, strings.Join(names, ", "), cc.ErrReturn(gen.ErrVariable(errVariable)))
+}
+
+// rawValue converts [target] to its underlying type,
+// so that custom String methods are not used when formatting errors
+func rawValue(ty an.Type, target string) string {
+	if _, isNamed := ty.Origin().(*types.Named); !isNamed {
+		return target
+	}
+	return fmt.Sprintf("%s(%s)", gen.TypeName(ty.Origin().Underlying()), target)
+}
+
+// enumsForTable returns the String methods of the
+// enums used by [ta] (or by its arrays and slices),
+// unless already defined by the user.
+func enumsForTable(ta an.Struct) []gen.Declaration {
+	var out []gen.Declaration
+	for _, field := range ta.Fields {
+		ty := field.Type
+		switch elem := ty.(type) {
+		case an.Array:
+			ty = elem.Elem
+		case an.Slice:
+			ty = elem.Elem
+		}
+		ba, isBasic := ty.(an.Basic)
+		if !isBasic || len(ba.Enum) == 0 {
+			continue
+		}
+		origin := ba.Origin().(*types.Named)
+		if obj, _, _ := types.LookupFieldOrMethod(origin, false, origin.Obj().Pkg(), "String"); obj != nil {
+			continue
+		}
+		typeName := origin.Obj().Name()
+		names := enumConstants(ba)
+		cases := make([]string, len(names))
+		for i, name := range names {
+			cases[i] = fmt.Sprintf(`case %s:
+				return "%s"`, name, name)
+		}
+		out = append(out, gen.Declaration{
+			ID:     typeName + ".String",
+			Origin: origin,
+			Content: fmt.Sprintf(`func (v %s) String() string {
+				switch v {
+				%s
+				default:
+					return fmt.Sprintf("%s(%%d)", %s)
+				}
+			}
+			`, typeName, strings.Join(cases, "\n"), typeName, rawValue(ba, "v")),
+		})
+	}
+	return out
+}
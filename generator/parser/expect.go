@@ -0,0 +1,67 @@
+package parser
+
+import (
+	"fmt"
+	"go/constant"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// fields with constant values are checked by the parsing function,
+// once the scope they belong to has been read
+
+// expectHelpers are sorted so that a declaration is listed
+// before the declarations it depends on
+var expectHelpers = []gen.Declaration{
+	{
+		ID: "expectError",
+		Content: `// expectError is returned when a field does not
+		// have one of its expected values
+		type expectError struct {
+			field    string
+			expected string
+			got      interface{}
+		}
+
+		func (err expectError) Error() string {
+			return fmt.Sprintf("invalid value for %s: expected %s, got %#x", err.field, err.expected, err.got)
+		}
+		`,
+	},
+}
+
+// expectChecks returns the checks for the fields with constant
+// values in [fs], or an empty string.
+// The fields must have been parsed by the caller.
+func expectChecks(fs an.StaticSizedFields, cc gen.Context) string {
+	var code []string
+	for _, field := range fs {
+		if len(field.Expect) == 0 {
+			continue
+		}
+		target := cc.Selector(field.Name)
+		conditions := make([]string, len(field.Expect))
+		expected := make([]string, len(field.Expect))
+		for i, value := range field.Expect {
+			conditions[i] = fmt.Sprintf("%s != %s", target, value.ExactString())
+			expected[i] = formatExpected(value)
+		}
+		errVariable := fmt.Sprintf(`expectError{field: "%s.%s", expected: "%s", got: %s}`,
+			cc.Type, field.Name, strings.Join(expected, " or "), rawValue(field.Type, target))
+		code = append(code, fmt.Sprintf(`if %s {
+			%s
+		}`, strings.Join(conditions, " && "), cc.ErrReturn(gen.ErrVariable(errVariable))))
+	}
+	return strings.Join(code, "\n")
+}
+
+// formatExpected uses the same format as the error message
+func formatExpected(value constant.Value) string {
+	if u, isExact := constant.Uint64Val(value); isExact {
+		return fmt.Sprintf("%#x", u)
+	}
+	i, _ := constant.Int64Val(value)
+	return fmt.Sprintf("%#x", i)
+}
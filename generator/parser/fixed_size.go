@@ -25,8 +25,16 @@ func mustParser(ty an.Type, cc gen.Context, target string) string {
 		return mustParserOffset(ty, cc, target)
 	case an.Slice:
 		return mustParseSlice(ty, cc, target)
+	case an.Map: // only the count prefix
+		return mustParseSlice(ty.Records, cc, target)
+	case an.String: // only the count prefix
+		return mustParseSlice(ty.Data, cc, target)
+	case an.Bitfield:
+		return mustParserBitfield(ty, cc)
+	case an.Reserved:
+		return "" // skipped, see [reservedChecks]
 	default:
-		// other types are never fixed sized
+		// other types are never fixed sized, or are fallible (see [parserChecked])
 		panic(fmt.Sprintf("invalid type %T in mustParser", ty))
 	}
 }
@@ -36,20 +44,193 @@ func mustParserBasic(bt an.Basic, cc gen.Context, target string) string {
 	readCode := readBasicTypeAt(cc, size)
 
 	name := gen.Name(bt)
-
-	switch name {
-	case "uint8", "byte", "uint16", "uint32", "uint64": // simplify by removing the unnecessary conversion
+	if bt.IsBool() { // non zero is true
+		if name == "bool" {
+			return fmt.Sprintf("%s = %s != 0", target, readCode)
+		}
+		return fmt.Sprintf("%s = %s(%s != 0)", target, name, readCode)
+	}
+	if bt.Origin().Underlying().(*types.Basic).Info()&types.IsFloat != 0 {
+		return fmt.Sprintf("%s = %s", target, floatFromBits(bt, name, readCode))
+	}
+	if bt.Signed { // sign extension
+		signed := strings.Replace(readType(size), "uint", "int", 1)
+		if size == an.Uint24 {
+			readCode = fmt.Sprintf("int32((%s)<<8) >> 8", readCode)
+		} else {
+			readCode = fmt.Sprintf("%s(%s)", signed, readCode)
+		}
+		if name == signed {
+			return fmt.Sprintf("%s = %s", target, readCode)
+		}
+		return fmt.Sprintf("%s = %s(%s)", target, name, readCode)
+	}
+	if name == readType(size) || (name == "byte" && size == an.Byte) { // simplify by removing the unnecessary conversion
 		return fmt.Sprintf("%s = %s", target, readCode)
+	}
+	return fmt.Sprintf("%s = %s(%s)", target, name, readCode)
+}
+
+// floatFromBits returns the expression converting
+// [readCode] to the float type [name]
+func floatFromBits(bt an.Basic, name string, readCode string) string {
+	switch bt.FloatEncoding {
+	case an.F2Dot14:
+		return fmt.Sprintf("%s(int16(%s)) / (1 << 14)", name, readCode)
+	case an.Fixed:
+		return fmt.Sprintf("%s(int32(%s)) / (1 << 16)", name, readCode)
+	}
+	size, _ := bt.IsFixedSize()
+	conv := "math.Float32frombits"
+	if size == an.Uint64 {
+		conv = "math.Float64frombits"
+	}
+	if name == "float32" && size == an.Uint32 || name == "float64" && size == an.Uint64 {
+		return fmt.Sprintf("%s(%s)", conv, readCode)
+	}
+	return fmt.Sprintf("%s(%s(%s))", name, conv, readCode)
+}
+
+// readType returns the Go type of the expression
+// returned by [readBasicTypeAt]
+func readType(size an.BinarySize) string {
+	switch size {
+	case an.Byte:
+		return "uint8"
+	case an.Uint16:
+		return "uint16"
+	case an.Uint24, an.Uint32:
+		return "uint32"
 	default:
-		return fmt.Sprintf("%s = %s(%s)", target, name, readCode)
+		return "uint64"
 	}
 }
 
+// only valid for infallible constructors, see [parserDerivedChecked]
 func mustParserDerived(de an.DerivedFromBasic, cc gen.Context, target string) string {
 	readCode := readBasicTypeAt(cc, de.Size)
+	if de.IsMethod {
+		return fmt.Sprintf("%s.fromUint(%s)", target, readCode)
+	}
 	return fmt.Sprintf("%s = %sFromUint(%s)", target, de.Name, readCode)
 }
 
+// parserChecked is the same as [mustParser] for the fallible
+// types (see [an.IsFallible]) of [field], but returns the errors.
+// The length must have been checked by the caller.
+func parserChecked(ty an.Type, field an.Field, cc gen.Context, target string) string {
+	switch ty := ty.(type) {
+	case an.DerivedFromBasic:
+		return parserDerivedChecked(ty, cc, target)
+	case an.Codec:
+		return parserCodecChecked(ty, cc, target)
+	case an.Struct:
+		return parserStructChecked(ty, field, cc, target)
+	case an.Array:
+		elemSize, _ := ty.Elem.IsFixedSize()
+		statements := make([]string, ty.Len)
+		for i := range statements {
+			statements[i] = parserChecked(ty.Elem, field, cc, fmt.Sprintf("%s[%d]", target, i))
+			cc.Offset.Increment(elemSize)
+		}
+		return strings.Join(statements, "\n")
+	default:
+		panic(fmt.Sprintf("invalid type %T in parserChecked", ty))
+	}
+}
+
+func parserDerivedChecked(de an.DerivedFromBasic, cc gen.Context, target string) string {
+	readCode := readBasicTypeAt(cc, de.Size)
+	errReturn := cc.ErrReturn(invalidValueError(target))
+	if de.IsMethod {
+		return fmt.Sprintf(`if err := %s.fromUint(%s); err != nil {
+			%s
+		}`, target, readCode, errReturn)
+	}
+	value := valueName(target)
+	return fmt.Sprintf(`%s, err := %sFromUint(%s)
+		if err != nil {
+			%s
+		}
+		%s = %s`, value, de.Name, readCode, errReturn, target, value)
+}
+
+// parserStructChecked calls the parsing function of the fixed size
+// struct [st], which has checks (see [an.Struct.NeedsParseFunction])
+func parserStructChecked(st an.Struct, field an.Field, cc gen.Context, target string) string {
+	args := resolveArguments(cc.ObjectVar, field.ArgumentsProvidedByFields, requiredArgs(st, field.Name))
+	value := valueName(target)
+	_, index, _ := strings.Cut(strings.TrimSuffix(target, "]"), "[")
+	return fmt.Sprintf(`%s, _, err := %s(%s[%s:], %s)
+		if err != nil {
+			%s
+		}
+		%s = %s`, value, parseFunction(st), cc.Slice, cc.Offset.Value(), args,
+		cc.ErrReturn(nestedErr(st, fieldPath(cc, field.Name, index))), target, value)
+}
+
+// valueName returns the name of the variable storing
+// the value of [target], before its assignment
+func valueName(target string) string {
+	_, name, ok := strings.Cut(target, ".")
+	if !ok {
+		name = target
+	}
+	name = strings.NewReplacer("[i]", "", "[", "", "]", "").Replace(name)
+	return "value" + strings.Title(name)
+}
+
+// invalidValueError wraps the "err" variable, reporting the
+// field of [target], and its index for the elements of slices,
+// indexed by "i"
+func invalidValueError(target string) gen.Err {
+	_, name, ok := strings.Cut(target, ".")
+	if !ok {
+		name = target
+	}
+	if strings.Contains(name, "[i]") {
+		return gen.ErrFormated(fmt.Sprintf(`"invalid %s: %%w", i, err`, strings.Replace(name, "[i]", "[%d]", 1)))
+	}
+	return gen.ErrFormated(fmt.Sprintf(`"invalid %s: %%w", err`, name))
+}
+
+// read the storage once and extract each member, with
+// a shift and a mask. Signed members are sign extended, by
+// shifting their bits to the top of the storage.
+func mustParserBitfield(bf an.Bitfield, cc gen.Context) string {
+	storageBits := 8 * int(bf.Storage)
+	statements := []string{fmt.Sprintf("bits := %s", readBasicTypeAt(cc, bf.Storage))}
+	for _, member := range bf.Members {
+		if member.Name == "_" { // unused bits
+			continue
+		}
+		target, typeName := cc.Selector(member.Name), gen.TypeName(member.Origin())
+		value := "bits"
+		if member.First != 0 {
+			value = fmt.Sprintf("(bits >> %d)", member.First)
+		}
+		switch {
+		case member.IsBool():
+			statements = append(statements, fmt.Sprintf("%s = %s&0x%x != 0", target, value, member.Mask()))
+		case member.IsSigned():
+			value = "bits"
+			if shift := storageBits - 1 - member.Last; shift != 0 {
+				value = fmt.Sprintf("bits<<%d", shift)
+			}
+			signed := fmt.Sprintf("int%d(%s) >> %d", storageBits, value, storageBits-(member.Last-member.First+1))
+			if typeName != fmt.Sprintf("int%d", storageBits) {
+				signed = fmt.Sprintf("%s(%s)", typeName, signed)
+			}
+			statements = append(statements, fmt.Sprintf("%s = %s", target, signed))
+		default:
+			statements = append(statements, fmt.Sprintf("%s = %s(%s & 0x%x)", target, typeName, value, member.Mask()))
+		}
+	}
+	return fmt.Sprintf(`{
+		%s
+	}`, strings.Join(statements, "\n"))
+}
+
 // only valid for fixed size structs, call the `mustParse` method
 func mustParserStruct(st an.Struct, cc gen.Context, target string) string {
 	return fmt.Sprintf("%s.mustParse(%s[%s:])", target, cc.Slice, cc.Offset.Value())
@@ -115,7 +296,11 @@ func mustParserFields(fs an.StaticSizedFields, cc *gen.Context) string {
 	}
 
 	for _, field := range fs {
-		code = append(code, mustParser(field.Type, *cc, cc.Selector(field.Name)))
+		if an.IsFallible(field.Type) { // not found in mustParse methods, see [an.Struct.NeedsParseFunction]
+			code = append(code, parserChecked(field.Type, field, *cc, cc.Selector(field.Name)))
+		} else {
+			code = append(code, mustParser(field.Type, *cc, cc.Selector(field.Name)))
+		}
 
 		fieldSize, _ := field.Type.IsFixedSize()
 		// adjust the offset
@@ -125,6 +310,19 @@ func mustParserFields(fs an.StaticSizedFields, cc *gen.Context) string {
 	return strings.Join(code, "\n")
 }
 
+// scopeChecks returns the checks performed once the
+// fields of [fs] have been read : reserved bytes, constant values,
+// strict bools and enums
+func scopeChecks(fs an.StaticSizedFields, cc gen.Context) string {
+	var code []string
+	for _, check := range []string{reservedChecks(fs, cc), expectChecks(fs, cc), valueChecks(fs, cc)} {
+		if check != "" {
+			code = append(code, check)
+		}
+	}
+	return strings.Join(code, "\n")
+}
+
 // return the mustParse method and the body of the parse function
 func mustParserFieldsFunction(ta an.Struct, cc gen.Context) (mustParse gen.Declaration, parseBody string) {
 	fs := ta.Scopes()[0].(an.StaticSizedFields)
@@ -139,7 +337,8 @@ func mustParserFieldsFunction(ta an.Struct, cc gen.Context) (mustParse gen.Decla
 	}
 	`, cc.ObjectVar, cc.Type, cc.Slice, mustParseBody)
 
-	// for the parsing function: check length, call mustParse, and update the offset
+	// for the parsing function: check length, call mustParse, and update the offset ;
+	// structs with checks are never parsed with mustParse (see [an.Struct.NeedsParseFunction])
 	check := staticLengthCheckAt(cc, fs.Size())
 	mustParseCall := fmt.Sprintf("%s.mustParse(%s)", cc.ObjectVar, cc.Slice)
 	updateOffset := cc.Offset.UpdateStatement(fs.Size())
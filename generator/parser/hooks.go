@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// hookCall returns the call of the user written method [hook],
+// with [params] and the arguments of the parsing function if needed
+func hookCall(hook an.Hook, ta an.Struct, cc gen.Context, params ...string) string {
+	if hook.WithArguments {
+		for _, arg := range userArguments(ta) {
+			params = append(params, arg.variableName)
+		}
+	}
+	call := fmt.Sprintf("%s.%s(%s)", cc.ObjectVar, hook.Name, strings.Join(params, ", "))
+	if !hook.ReturnsError {
+		return call
+	}
+	return fmt.Sprintf(`if err := %s; err != nil {
+		%s
+	}`, call, cc.ErrReturn(gen.ErrVariable("err")))
+}
+
+// afterHooks returns the calls of the after<Field> hooks,
+// for the fields of [scope] which are completely read.
+// Offsets and slices are only complete in their [an.SingleField] scope.
+func afterHooks(scope an.Scope, ta an.Struct, cc gen.Context) string {
+	var fields []an.Field
+	switch scope := scope.(type) {
+	case an.StaticSizedFields:
+		fields = scope
+	case an.OptionalFields:
+		fields = scope
+	case an.SingleField:
+		fields = []an.Field{an.Field(scope)}
+	}
+	_, isSingle := scope.(an.SingleField)
+
+	var code []string
+	for _, field := range fields {
+		if field.After == nil {
+			continue
+		}
+		if _, isFixedSize := field.Type.IsFixedSize(); !isFixedSize && !isSingle {
+			continue
+		}
+		call := hookCall(*field.After, ta, cc)
+		if !field.PresentIf.IsEmpty() { // only call the hook for present fields
+			call = fmt.Sprintf(`if %s {
+				%s
+			}`, conditionalField{name: field.Name}.variableName(), call)
+		}
+		code = append(code, call)
+	}
+	return strings.Join(code, "\n")
+}
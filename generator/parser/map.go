@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"fmt"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// parserForMap parses the array of records, and then
+// builds the map, reporting duplicated keys.
+// The generated code will look like
+//
+//	var records []TableRecord
+//	<parse the records>
+//	item.tables = make(map[Tag]TableRecord, len(records))
+//	for _, record := range records {
+//		if _, isDuplicate := item.tables[record.tag]; isDuplicate {
+//			return err
+//		}
+//		item.tables[record.tag] = record
+//	}
+func parserForMap(field an.Field, cc *gen.Context) string {
+	m := field.Type.(an.Map)
+	target := cc.Selector(field.Name)
+	const records = "records"
+
+	errReturn := cc.ErrReturn(gen.ErrFormated(fmt.Sprintf(`"duplicate key %%v in %s", record.%s`, field.Name, m.Key)))
+	return fmt.Sprintf(`var %s []%s
+		%s
+		%s = make(%s, len(%s))
+		for _, record := range %s {
+			if _, isDuplicate := %s[record.%s]; isDuplicate {
+				%s
+			}
+			%s[record.%s] = record
+		}
+		`, records, gen.Name(m.Records.Elem),
+		parserForSliceTo(m.Records, field, cc, records),
+		target, gen.Name(m), records,
+		records,
+		target, m.Key,
+		errReturn,
+		target, m.Key,
+	)
+}
@@ -0,0 +1,79 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// optional fields are only present when their condition is non zero.
+// The conditions of a scope are evaluated first, so that the length
+// is checked once, before reading the fields.
+// The generated code will look like
+//
+//	hasXPlacement := valueFormat&0x0001 != 0
+//	{ <conditional length check> }
+//	if hasXPlacement {
+//		item.xPlacement = int16(binary.BigEndian.Uint16(src[n:]))
+//		n += 2
+//	}
+func parserForOptionalFields(fs an.OptionalFields, cc *gen.Context) string {
+	var (
+		code       []string
+		baseSize   an.BinarySize
+		conditions []conditionalField
+	)
+	for _, field := range fs {
+		size, _ := field.Type.IsFixedSize()
+		if field.PresentIf.IsEmpty() {
+			baseSize += size
+			continue
+		}
+		cd := conditionalField{name: field.Name, size: int(size)}
+		conditions = append(conditions, cd)
+		code = append(code, fmt.Sprintf("%s := %s != 0", cd.variableName(), field.PresentIf.Code(cc.ObjectVar)))
+		if _, isOffset := field.Type.(an.Offset); isOffset { // absent offsets are null
+			code = append(code, fmt.Sprintf("var %s int", offsetName(cc.Selector(field.Name))))
+		}
+	}
+	code = append(code, conditionalLengthCheck(conditionalLength{
+		baseLength: cc.Offset.With(baseSize),
+		conditions: conditions,
+	}, *cc))
+
+	// read the contiguous required fields at once
+	var required an.StaticSizedFields
+	flushRequired := func() {
+		if len(required) != 0 {
+			code = append(code, readFixedSize(required, cc))
+		}
+		required = nil
+	}
+	for _, field := range fs {
+		if field.PresentIf.IsEmpty() {
+			required = append(required, field)
+			continue
+		}
+		flushRequired()
+
+		branch := *cc
+		var read string
+		if of, isOffset := field.Type.(an.Offset); isOffset {
+			read = fmt.Sprintf("%s = int(%s)\n%s", offsetName(cc.Selector(field.Name)),
+				readBasicTypeAt(branch, of.Size), branch.Offset.UpdateStatement(of.Size))
+		} else {
+			read = readFixedSize(an.StaticSizedFields{field}, &branch)
+		}
+		code = append(code, fmt.Sprintf(`if %s {
+			%s
+		}`, conditionalField{name: field.Name}.variableName(), read))
+
+		// the offset is now only known at runtime
+		cc.Offset = gen.NewOffsetDynamic(cc.Offset.Name)
+	}
+	flushRequired()
+
+	return strings.Join(code, "\n")
+}
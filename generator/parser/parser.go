@@ -18,6 +18,9 @@ func readBasicTypeAt(cc gen.Context, size an.BinarySize) string {
 		return fmt.Sprintf("%s[%s]", sliceName, offset)
 	case an.Uint16:
 		return fmt.Sprintf("binary.BigEndian.Uint16(%s[%s:])", sliceName, offset)
+	case an.Uint24:
+		return fmt.Sprintf("uint32(%s[%s])<<16 | uint32(%s[%s])<<8 | uint32(%s[%s])",
+			sliceName, offset, sliceName, cc.Offset.With(1), sliceName, cc.Offset.With(2))
 	case an.Uint32:
 		return fmt.Sprintf("binary.BigEndian.Uint32(%s[%s:])", sliceName, offset)
 	case an.Uint64:
@@ -27,6 +30,18 @@ func readBasicTypeAt(cc gen.Context, size an.BinarySize) string {
 	}
 }
 
+// addHelpers adds the helpers needed by the
+// declarations already in [dst].
+// [helpers] must be sorted so that a declaration is listed
+// before the declarations it depends on.
+func addHelpers(dst *gen.Buffer, helpers []gen.Declaration) {
+	for _, helper := range helpers {
+		if dst.IsUsed(helper.ID) {
+			dst.Add(helper)
+		}
+	}
+}
+
 // instruction to check the length of <sliceName>
 // the `Context` is used to generate the proper error return statement,
 // and to identify the input slice
@@ -169,8 +184,21 @@ func requiredArgs(ty an.Type, fieldName string) []argument {
 			args = append(args, requiredArgs(elem.Target, fieldName)...) // recurse for the offset target
 		}
 		return args
+	case an.Array:
+		return requiredArgs(ty.Elem, fieldName)
+	case an.Map:
+		return requiredArgs(ty.Records, fieldName)
+	case an.String:
+		if ty.FixedLength == 0 {
+			return requiredArgs(ty.Data, fieldName)
+		}
 	case an.Offset:
 		return requiredArgs(ty.Target, fieldName)
+	case an.Compressed:
+		// the length of raw data is the uncompressed length
+		if _, isStruct := ty.Target.(an.Struct); isStruct {
+			return requiredArgs(ty.Target, fieldName)
+		}
 	}
 	return nil
 }
@@ -209,7 +237,10 @@ func requiredArgsForStruct(st an.Struct) (args []argument) {
 	}
 	// add the user provided one
 	for _, arg := range st.Arguments {
-		args = append(args, argument{variableName: arg.VariableName, typeName: arg.TypeName})
+		if arg := (argument{variableName: arg.VariableName, typeName: arg.TypeName}); !seen[arg] {
+			args = append(args, arg)
+			seen[arg] = true
+		}
 	}
 	return args
 }
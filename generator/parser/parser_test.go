@@ -128,3 +128,16 @@ func TestWithArray(t *testing.T) {
 		}
 	}
 }
+
+func TestHelpers(t *testing.T) {
+	var helpers []gen.Declaration
+	for _, list := range [][]gen.Declaration{varIntHelpers, compressionHelpers, checksumHelpers, expectHelpers, validateHelpers, stringHelpers} {
+		helpers = append(helpers, list...)
+	}
+	for _, helper := range helpers {
+		_, err := pa.ParseFile(token.NewFileSet(), "", "package main\n"+helper.Content, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
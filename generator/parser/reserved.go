@@ -0,0 +1,62 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// reserved fields and padding are skipped.
+// In strict mode, their bytes are checked to be zero by the parsing function,
+// which is also used for elements of slices and arrays (see [an.Struct.IsFixedSize]).
+
+// zeroCheck returns the code checking that
+// the bytes in [start, end) are zero
+func zeroCheck(cc gen.Context, start, end gen.Expression) string {
+	errReturn := cc.ErrReturn(gen.ErrFormated(fmt.Sprintf(`"non zero reserved byte at %%d", %s + i`, start)))
+	return fmt.Sprintf(`for i, b := range %s[%s:%s] {
+		if b != 0 {
+			%s
+		}
+	}`, cc.Slice, start, end, errReturn)
+}
+
+// reservedChecks returns the zero checks for the strict
+// reserved fields in [fs], or an empty string.
+// The bounds must have been checked by the caller.
+func reservedChecks(fs an.StaticSizedFields, cc gen.Context) string {
+	var code []string
+	for _, field := range fs {
+		fieldSize, _ := field.Type.IsFixedSize()
+		if re, isReserved := field.Type.(an.Reserved); isReserved && re.Strict {
+			code = append(code, zeroCheck(cc, cc.Offset.Value(), cc.Offset.With(fieldSize)))
+		}
+		cc.Offset.Increment(fieldSize)
+	}
+	return strings.Join(code, "\n")
+}
+
+// parserForPadding skips the bytes up to the next multiple
+// of the alignment, relative to the start of the slice.
+// The generated code will look like
+//
+//	padding := (4 - n%4) % 4
+//	if L := len(src); L < n+padding {
+//		return err
+//	}
+//	n += padding
+func parserForPadding(field an.Field, cc *gen.Context) string {
+	pa := field.Type.(an.Padding)
+	start := cc.Offset.Value()
+	code := fmt.Sprintf(`padding := (%d - (%s)%%%d) %% %d
+		%s
+		`, pa.Align, start, pa.Align, pa.Align,
+		lengthCheck(*cc, fmt.Sprintf("%s + padding", start)),
+	)
+	if pa.Strict {
+		code += zeroCheck(*cc, start, fmt.Sprintf("%s + padding", start)) + "\n"
+	}
+	return code + cc.Offset.UpdateStatementDynamic("padding")
+}
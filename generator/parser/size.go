@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// structs whose size only depends on the arguments of their parsing function
+// (see [an.Struct.IsSizedByArguments]) have a generated size function, so that
+// slices of such structs are handled as slices of fixed size elements
+
+func sizeFunctionName(typeName string) string { return "size" + strings.Title(typeName) }
+
+// sizeForTable returns the function computing the size of [ta],
+// if it only depends on the arguments
+func sizeForTable(ta an.Struct) []gen.Declaration {
+	if !ta.IsSizedByArguments() {
+		return nil
+	}
+
+	var (
+		baseSize an.BinarySize
+		dynamic  []string
+		args     []string
+	)
+	for _, field := range ta.Fields {
+		size, isFixedSize := field.Type.IsFixedSize()
+		if !field.PresentIf.IsEmpty() {
+			dynamic = append(dynamic, fmt.Sprintf(`if %s != 0 {
+				size += %d
+			}`, field.PresentIf.Source, size))
+			continue
+		} else if isFixedSize {
+			baseSize += size
+			continue
+		}
+		switch ty := field.Type.(type) {
+		case an.Offset:
+			baseSize += ty.Size
+		case an.Slice:
+			dynamic = append(dynamic, "size += "+sliceSizeByArguments(ty, field.Name))
+		case an.Struct:
+			dynamic = append(dynamic, fmt.Sprintf("size += %s(%s)",
+				sizeFunctionName(ty.Origin().(*types.Named).Obj().Name()), resolveArguments("", nil, requiredArgs(ty, field.Name))))
+		}
+	}
+	for _, arg := range requiredArgs(ta, "") {
+		args = append(args, arg.asSignature())
+	}
+
+	body := fmt.Sprintf("return %d", baseSize)
+	if len(dynamic) != 0 {
+		body = fmt.Sprintf(`size := %d
+		%s
+		return size`, baseSize, strings.Join(dynamic, "\n"))
+	}
+
+	origin := ta.Origin().(*types.Named)
+	typeName := origin.Obj().Name()
+	content := fmt.Sprintf(`// %s returns the size of a %s, which only depends on the arguments
+	func %s(%s) int {
+		%s
+	}
+	`, sizeFunctionName(typeName), typeName, sizeFunctionName(typeName), strings.Join(args, ", "), body)
+
+	return []gen.Declaration{{ID: sizeFunctionName(typeName), Content: content, Origin: origin}}
+}
+
+// sliceSizeByArguments returns the byte length of [sl],
+// whose length is provided as argument
+func sliceSizeByArguments(sl an.Slice, fieldName string) gen.Expression {
+	count := externalCountVariable(fieldName)
+	if !sl.ByteWidth.IsEmpty() {
+		return fmt.Sprintf("%s * int(%s)", count, sl.ByteWidth.Source)
+	} else if sl.IsBitStream() {
+		return fmt.Sprintf("(%s*(%s) + 7) / 8", count, sl.BitWidth.IntCode(""))
+	}
+	elemSize, _ := sl.Elem.IsFixedSize() // also valid for offsets
+	return gen.ArrayOffset("", count, int(elemSize))
+}
+
+// The field is a slice of structs whose size only depends on the arguments :
+// it is the same for all the elements, and computed once.
+// The generated code will look like
+//
+//	elementSize := sizeValueRecord(valueFormat)
+//	if len(src) < n + arrayLength*elementSize {
+//		return err
+//	}
+//	out = make([]ValueRecord, arrayLength)
+//	for i := range out {
+//		out[i], _, err = parseValueRecord(src[n+i*elementSize:], valueFormat)
+//	}
+//	n += arrayLength*elementSize
+func parserForSliceSizedByArguments(st an.Struct, cc *gen.Context, count gen.Expression, field an.Field, target string) string {
+	sizeArgs := resolveArguments(cc.ObjectVar, field.ArgumentsProvidedByFields, requiredArgs(st, field.Name))
+	args := resolveSliceArgument(field.Type, *cc) + sizeArgs
+	byteLength := count + "*elementSize"
+
+	out := fmt.Sprintf(`elementSize := %s(%s)
+		%s
+		%s = make([]%s, %s) // allocation guarded by the previous check
+		for i := range %s {
+			var err error
+			%s[i], _, err = %s(%s[%s:], %s)
+			if err != nil {
+				%s
+			}
+		}
+		`, sizeFunctionName(st.Origin().(*types.Named).Obj().Name()), sizeArgs,
+		lengthCheck(*cc, cc.Offset.WithAffine(byteLength, 1)),
+		target, gen.Name(st), count,
+		target,
+		target, parseFunction(st), cc.Slice, cc.Offset.WithAffine("i*elementSize", 1), args,
+		cc.ErrReturn(nestedErr(st, fieldPath(*cc, field.Name, "i"))),
+	)
+
+	return out + cc.Offset.UpdateStatementDynamic(byteLength)
+}
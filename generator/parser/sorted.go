@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// slices of records sorted by a key are searched with
+// generated methods Find<Field>, working on the parsed slice
+
+func findName(fieldName string) string {
+	return "Find" + strings.Title(fieldName)
+}
+
+// findersForTable returns the binary search methods
+// for the sorted slices of [ta]
+func findersForTable(ta an.Struct) []gen.Declaration {
+	origin := ta.Origin().(*types.Named)
+	typeName := origin.Obj().Name()
+	const objectVar = "item"
+
+	var out []gen.Declaration
+	for _, field := range ta.Fields {
+		sl, isSlice := field.Type.(an.Slice)
+		if !isSlice || sl.SortedBy.IsEmpty() {
+			continue
+		}
+		key := sl.SortedBy
+		methodName := findName(field.Name)
+
+		var comment, comparison string
+		if key.IsRange() {
+			comment = fmt.Sprintf(`// %s returns the index of the record in [%s] whose range [%s, %s]
+			// contains [key], using a binary search.`, methodName, field.Name, key.Start, key.End)
+			comparison = fmt.Sprintf(`if key < records[mid].%s {
+				high = mid
+			} else if key > records[mid].%s {
+				low = mid + 1
+			} else {
+				return mid, true
+			}`, key.Start, key.End)
+		} else {
+			comment = fmt.Sprintf(`// %s returns the index of the record in [%s] whose %s is [key],
+			// using a binary search.`, methodName, field.Name, key.Start)
+			comparison = fmt.Sprintf(`if k := records[mid].%s; key < k {
+				high = mid
+			} else if key > k {
+				low = mid + 1
+			} else {
+				return mid, true
+			}`, key.Start)
+		}
+
+		out = append(out, gen.Declaration{
+			ID:     typeName + "." + methodName,
+			Origin: origin,
+			Content: fmt.Sprintf(`%s
+			func (%s *%s) %s(key %s) (int, bool) {
+				records := %s.%s
+				low, high := 0, len(records)
+				for low < high {
+					mid := low + (high-low)/2
+					%s
+				}
+				return -1, false
+			}
+			`, comment, objectVar, typeName, methodName, gen.TypeName(key.Origin()),
+				objectVar, field.Name, comparison),
+		})
+	}
+	return out
+}
+
+// sortedCheck returns the code checking the order of the
+// records of the slice [fieldName], stored in [target], or an empty string
+func sortedCheck(sl an.Slice, cc gen.Context, fieldName, target string) string {
+	key := sl.SortedBy
+	if !key.Check {
+		return ""
+	}
+	errReturn := cc.ErrReturn(gen.ErrFormated(fmt.Sprintf(`"%s not sorted at index %%d", i`, fieldName)))
+	if key.IsRange() {
+		return fmt.Sprintf(`for i, record := range %s {
+			if record.%s > record.%s || (i != 0 && %s[i-1].%s >= record.%s) {
+				%s
+			}
+		}`, target, key.Start, key.End, target, key.End, key.Start, errReturn)
+	}
+	return fmt.Sprintf(`for i := 1; i < len(%s); i++ {
+		if %s[i-1].%s >= %s[i].%s {
+			%s
+		}
+	}`, target, target, key.Start, target, key.Start, errReturn)
+}
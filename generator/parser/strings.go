@@ -0,0 +1,208 @@
+package parser
+
+import (
+	"fmt"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// strings are decoded by helper functions,
+// which are only added to the output when used
+
+// stringHelpers are sorted so that a declaration is listed
+// before the declarations it depends on
+var stringHelpers = []gen.Declaration{
+	{
+		ID: "decodeUTF8",
+		Content: `// decodeUTF8 checks that [data] is valid UTF-8
+		func decodeUTF8(data []byte) (string, error) {
+			for i := 0; i < len(data); {
+				r, size := utf8.DecodeRune(data[i:])
+				if r == utf8.RuneError && size <= 1 {
+					return "", stringError{encoding: "UTF-8", index: i}
+				}
+				i += size
+			}
+			return string(data), nil
+		}
+		`,
+	},
+	{
+		ID: "decodeASCII",
+		Content: `// decodeASCII checks that [data] only contains ASCII characters
+		func decodeASCII(data []byte) (string, error) {
+			for i, b := range data {
+				if b >= 0x80 {
+					return "", stringError{encoding: "ASCII", index: i}
+				}
+			}
+			return string(data), nil
+		}
+		`,
+	},
+	{
+		ID: "decodeUTF16BE",
+		Content: `// decodeUTF16BE decodes [data], rejecting invalid surrogates
+		func decodeUTF16BE(data []byte) (string, error) {
+			if len(data)%2 != 0 {
+				return "", stringError{encoding: "UTF-16BE", index: len(data) - 1}
+			}
+			var out strings.Builder
+			for i := 0; i < len(data); i += 2 {
+				u := rune(data[i])<<8 | rune(data[i+1])
+				switch {
+				case 0xD800 <= u && u < 0xDC00: // high surrogate
+					if i+4 > len(data) {
+						return "", stringError{encoding: "UTF-16BE", index: i}
+					}
+					low := rune(data[i+2])<<8 | rune(data[i+3])
+					if !(0xDC00 <= low && low < 0xE000) {
+						return "", stringError{encoding: "UTF-16BE", index: i}
+					}
+					out.WriteRune(utf16.DecodeRune(u, low))
+					i += 2
+				case 0xDC00 <= u && u < 0xE000: // unpaired low surrogate
+					return "", stringError{encoding: "UTF-16BE", index: i}
+				default:
+					out.WriteRune(u)
+				}
+			}
+			return out.String(), nil
+		}
+		`,
+	},
+	{
+		ID: "decodeMacRoman",
+		Content: `// decodeMacRoman decodes [data], which is always valid
+		func decodeMacRoman(data []byte) (string, error) {
+			out := make([]rune, len(data))
+			for i, b := range data {
+				if b < 0x80 {
+					out[i] = rune(b)
+				} else {
+					out[i] = macRomanHigh[b-0x80]
+				}
+			}
+			return string(out), nil
+		}
+		`,
+	},
+	{
+		ID: "macRomanHigh",
+		Content: `// macRomanHigh maps the bytes 0x80 - 0xFF of the Mac OS Roman encoding
+		var macRomanHigh = [128]rune{
+			0x00C4, 0x00C5, 0x00C7, 0x00C9, 0x00D1, 0x00D6, 0x00DC, 0x00E1,
+			0x00E0, 0x00E2, 0x00E4, 0x00E3, 0x00E5, 0x00E7, 0x00E9, 0x00E8,
+			0x00EA, 0x00EB, 0x00ED, 0x00EC, 0x00EE, 0x00EF, 0x00F1, 0x00F3,
+			0x00F2, 0x00F4, 0x00F6, 0x00F5, 0x00FA, 0x00F9, 0x00FB, 0x00FC,
+			0x2020, 0x00B0, 0x00A2, 0x00A3, 0x00A7, 0x2022, 0x00B6, 0x00DF,
+			0x00AE, 0x00A9, 0x2122, 0x00B4, 0x00A8, 0x2260, 0x00C6, 0x00D8,
+			0x221E, 0x00B1, 0x2264, 0x2265, 0x00A5, 0x00B5, 0x2202, 0x2211,
+			0x220F, 0x03C0, 0x222B, 0x00AA, 0x00BA, 0x03A9, 0x00E6, 0x00F8,
+			0x00BF, 0x00A1, 0x00AC, 0x221A, 0x0192, 0x2248, 0x2206, 0x00AB,
+			0x00BB, 0x2026, 0x00A0, 0x00C0, 0x00C3, 0x00D5, 0x0152, 0x0153,
+			0x2013, 0x2014, 0x201C, 0x201D, 0x2018, 0x2019, 0x00F7, 0x25CA,
+			0x00FF, 0x0178, 0x2044, 0x20AC, 0x2039, 0x203A, 0xFB01, 0xFB02,
+			0x2021, 0x00B7, 0x201A, 0x201E, 0x2030, 0x00C2, 0x00CA, 0x00C1,
+			0x00CB, 0x00C8, 0x00CD, 0x00CE, 0x00CF, 0x00CC, 0x00D3, 0x00D4,
+			0xF8FF, 0x00D2, 0x00DA, 0x00DB, 0x00D9, 0x0131, 0x02C6, 0x02DC,
+			0x00AF, 0x02D8, 0x02D9, 0x02DA, 0x00B8, 0x02DD, 0x02DB, 0x02C7,
+		}
+		`,
+	},
+	{
+		ID: "trimZeroPadding",
+		Content: `// trimZeroPadding removes the trailing zeros of [data],
+		// for characters using [unitSize] bytes
+		func trimZeroPadding(data []byte, unitSize int) []byte {
+			for len(data) >= unitSize {
+				for _, b := range data[len(data)-unitSize:] {
+					if b != 0 {
+						return data
+					}
+				}
+				data = data[:len(data)-unitSize]
+			}
+			return data
+		}
+		`,
+	},
+	{
+		ID: "stringError",
+		Content: `// stringError is returned when the bytes of
+		// a string are not valid for its encoding
+		type stringError struct {
+			encoding string
+			index    int // in the string data
+		}
+
+		func (err stringError) Error() string {
+			return fmt.Sprintf("invalid %s data at byte %d", err.encoding, err.index)
+		}
+		`,
+	},
+}
+
+func stringDecoder(encoding an.StringEncoding) string {
+	switch encoding {
+	case an.UTF8:
+		return "decodeUTF8"
+	case an.ASCII:
+		return "decodeASCII"
+	case an.UTF16BE:
+		return "decodeUTF16BE"
+	case an.MacRoman:
+		return "decodeMacRoman"
+	default:
+		panic("exhaustive switch")
+	}
+}
+
+// parserForString reads the bytes of the string, and then decodes them.
+// The generated code will look like
+//
+//	var raw []byte
+//	<read the raw data>
+//	s, err := decodeUTF16BE(raw)
+//	if err != nil {
+//		return err
+//	}
+//	item.name = s
+func parserForString(field an.Field, cc *gen.Context) string {
+	st := field.Type.(an.String)
+	const raw = "raw"
+
+	var code string
+	if st.FixedLength != 0 {
+		unitSize := 1
+		if st.Encoding == an.UTF16BE {
+			unitSize = 2
+		}
+		code = fmt.Sprintf(`%s
+			%s := trimZeroPadding(%s[%s:%s], %d)
+			`, staticLengthCheckAt(*cc, st.FixedLength),
+			raw, cc.Slice, cc.Offset.Value(), cc.Offset.With(st.FixedLength), unitSize,
+		)
+		if !cc.IgnoreUpdateOffset {
+			cc.Offset.Increment(st.FixedLength)
+			code += cc.Offset.UpdateStatement(st.FixedLength) + "\n"
+		}
+	} else {
+		code = fmt.Sprintf(`var %s []byte
+			%s
+			`, raw, parserForSliceTo(st.Data, field, cc, raw))
+	}
+
+	value := "s"
+	if name := gen.Name(st); name != "string" {
+		value = fmt.Sprintf("%s(s)", name)
+	}
+	errReturn := cc.ErrReturn(gen.ErrFormated(fmt.Sprintf(`"invalid %s: %%w", err`, field.Name)))
+	return code + fmt.Sprintf(`s, err := %s(%s)
+		if err != nil {
+			%s
+		}
+		%s = %s
+		`, stringDecoder(st.Encoding), raw, errReturn, cc.Selector(field.Name), value)
+}
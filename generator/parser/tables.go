@@ -10,15 +10,38 @@ import (
 
 // ParsersForFile write the parsing functions required by [ana.Tables] in [dst]
 func ParsersForFile(ana an.Analyser, dst *gen.Buffer) {
+	gen.SetLocalPackage(ana.Package())
 	for _, table := range ana.Tables {
 		for _, decl := range parserForTable(table) {
 			dst.Add(decl)
 		}
+		for _, decl := range checksumsForTable(table) {
+			dst.Add(decl)
+		}
+		for _, decl := range validateForTable(table) {
+			dst.Add(decl)
+		}
+		for _, decl := range findersForTable(table) {
+			dst.Add(decl)
+		}
+		for _, decl := range enumsForTable(table) {
+			dst.Add(decl)
+		}
+		for _, decl := range sizeForTable(table) {
+			dst.Add(decl)
+		}
 	}
 
 	for _, standaloneUnion := range ana.StandaloneUnions {
 		dst.Add(parserForStanaloneUnion(standaloneUnion))
 	}
+
+	addHelpers(dst, varIntHelpers)
+	addHelpers(dst, compressionHelpers)
+	addHelpers(dst, checksumHelpers)
+	addHelpers(dst, expectHelpers)
+	addHelpers(dst, validateHelpers)
+	addHelpers(dst, stringHelpers)
 }
 
 // parserForTable returns the parsing function for the given table.
@@ -49,29 +72,42 @@ func parserForTable(ta an.Struct) []gen.Declaration {
 	for _, arg := range requiredArgs(ta, "") {
 		args = append(args, arg.asSignature())
 	}
+	if ta.ParseStart != nil {
+		body = append(body, hookCall(*ta.ParseStart, ta, *context, context.Slice))
+	}
 
-	// important special case when all fields have fixed size (with no offset) :
-	// generate a mustParse method
-	if _, isFixedSize := ta.IsFixedSize(); isFixedSize {
+	// important special case when all fields have fixed size (with no offset),
+	// and may not fail once the length is checked : generate a mustParse method
+	if _, isFixedSize := ta.IsFixedSize(); isFixedSize && !ta.NeedsParseFunction() {
 		mustParse, parseBody := mustParserFieldsFunction(ta, *context)
 		body = append(body, parseBody)
+		if call := validateCall(ta, *context); call != "" {
+			body = append(body, call)
+		}
 
 		return []gen.Declaration{mustParse, context.ParsingFuncComment(origin, args, body, "")}
 	}
 
 	for _, scope := range scopes {
 		body = append(body, parser(scope, ta, context))
+		if hooks := afterHooks(scope, ta, *context); hooks != "" {
+			body = append(body, hooks)
+		}
 	}
 	// add the parseEnd when present
 	if ta.ParseEnd != nil {
-		body = append(body, fmt.Sprintf(`var err error 
-		n, err = %s.%s(%s, %s)
-		if err != nil {
-			%s
-		}
-		`, context.ObjectVar, ta.ParseEnd.Name(), context.Slice, resolveArguments(context.ObjectVar, nil, requiredArgs(ta, "")),
+		body = append(body, fmt.Sprintf(`{
+			var err error
+			n, err = %s.%s(%s, %s)
+			if err != nil {
+				%s
+			}
+		}`, context.ObjectVar, ta.ParseEnd.Name(), context.Slice, resolveArguments(context.ObjectVar, nil, requiredArgs(ta, "")),
 			context.ErrReturn(gen.ErrVariable("err"))))
 	}
+	if call := validateCall(ta, *context); call != "" {
+		body = append(body, call)
+	}
 
 	finalCode := context.ParsingFuncComment(origin, args, body, "")
 
@@ -92,7 +128,7 @@ func parserForStanaloneUnion(un an.Union) gen.Declaration {
 		args = append(args, arg.asSignature())
 	}
 
-	cases := unionCases(un, context, nil, context.ObjectVar)
+	cases := unionCases(un, context, nil, context.ObjectVar, "")
 	code := standaloneUnionBody(un, context, cases)
 	body = append(body, code)
 
@@ -106,6 +142,8 @@ func parser(scope an.Scope, parent an.Struct, cc *gen.Context) string {
 	switch scope := scope.(type) {
 	case an.StaticSizedFields:
 		code = parserForFixedSize(scope, cc)
+	case an.OptionalFields:
+		code = parserForOptionalFields(scope, cc)
 	case an.SingleField:
 		code = parserForSingleField(scope, parent, cc)
 	default:
@@ -116,13 +154,20 @@ func parser(scope an.Scope, parent an.Struct, cc *gen.Context) string {
 
 // add the length check
 func parserForFixedSize(fs an.StaticSizedFields, cc *gen.Context) string {
+	return staticLengthCheckAt(*cc, fs.Size()) + "\n" + readFixedSize(fs, cc)
+}
+
+// readFixedSize reads the fields and performs the scope checks,
+// the length must have been checked by the caller
+func readFixedSize(fs an.StaticSizedFields, cc *gen.Context) string {
 	totalSize := fs.Size()
+	checks := scopeChecks(fs, *cc)
 	return fmt.Sprintf(`%s
 		%s
 		%s
 		`,
-		staticLengthCheckAt(*cc, totalSize),
 		mustParserFields(fs, cc),
+		checks,
 		cc.Offset.UpdateStatement(totalSize),
 	)
 }
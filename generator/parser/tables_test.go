@@ -20,6 +20,7 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	gen.SetLocalPackage(ana.Package())
 }
 
 func TestGenerateParser(t *testing.T) {
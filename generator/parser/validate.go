@@ -0,0 +1,228 @@
+package parser
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// constraints on fields are checked by a generated method,
+// validate(<arguments>) error, called at the end of the parsing function
+
+// validateHelpers are sorted so that a declaration is listed
+// before the declarations it depends on
+var validateHelpers = []gen.Declaration{
+	{
+		ID: "nestedValidationError",
+		Content: `// nestedValidationError prefixes the path of the [validationError]
+		// wrapped in [err], if any, with [parent], the path of the field storing
+		// the nested value. The other errors are returned unchanged.
+		func nestedValidationError(err error, parent string) error {
+			var ve validationError
+			if !errors.As(err, &ve) {
+				return err
+			}
+			if i := strings.IndexByte(ve.path, '.'); i != -1 { // remove the nested type name
+				ve.path = parent + ve.path[i:]
+			}
+			return ve
+		}
+		`,
+	},
+	{
+		ID: "validationError",
+		Content: `// validationError is returned when a field
+		// does not satisfy its constraints
+		type validationError struct {
+			path       string // <Type>.<field>, with an index for arrays, followed by the fields of nested structs
+			constraint string
+			value      interface{}
+		}
+
+		func (err validationError) Error() string {
+			return fmt.Sprintf("invalid value %v for %s: expected %s", err.value, err.path, err.constraint)
+		}
+		`,
+	},
+}
+
+// userArguments returns the arguments provided to the parsing function
+// by the user, which are also the arguments of the validate method
+func userArguments(ta an.Struct) []argument {
+	args := make([]argument, len(ta.Arguments))
+	for i, arg := range ta.Arguments {
+		args[i] = argument{variableName: arg.VariableName, typeName: arg.TypeName}
+	}
+	return args
+}
+
+// mayReturnValidationError returns true if the parsing function of [ty]
+// may return a validationError, for itself or for one of its nested values.
+func mayReturnValidationError(ty an.Type, visited map[types.Type]bool) bool {
+	switch ty := ty.(type) {
+	case an.Struct:
+		if visited[ty.Origin()] { // recursive types
+			return false
+		}
+		visited[ty.Origin()] = true
+		if ty.HasConstraints() {
+			return true
+		}
+		for _, field := range ty.Fields {
+			if mayReturnValidationError(field.Type, visited) {
+				return true
+			}
+		}
+	case an.Union:
+		for _, member := range ty.Members {
+			if mayReturnValidationError(member, visited) {
+				return true
+			}
+		}
+	case an.Slice:
+		return mayReturnValidationError(ty.Elem, visited)
+	case an.Array:
+		return mayReturnValidationError(ty.Elem, visited)
+	case an.Map:
+		return mayReturnValidationError(ty.Records, visited)
+	case an.Offset:
+		return mayReturnValidationError(ty.Target, visited)
+	case an.Compressed:
+		return mayReturnValidationError(ty.Target, visited)
+	}
+	return false
+}
+
+// nestedErr returns the error wrapping the "err" variable, returned by the parsing
+// function of [ty], whose validation path is prefixed by [path] (a Go string expression)
+func nestedErr(ty an.Type, path gen.Expression) gen.Err {
+	if !mayReturnValidationError(ty, map[types.Type]bool{}) {
+		return gen.ErrVariable("err")
+	}
+	return gen.ErrFormated(fmt.Sprintf(`"%%w", nestedValidationError(err, %s)`, path))
+}
+
+// fieldPath returns the path of [fieldName] in the type of [cc], as a Go string expression.
+// [index] is empty, or is the index of the element of arrays and slices,
+// either a constant or the "i" loop variable
+func fieldPath(cc gen.Context, fieldName string, index string) gen.Expression {
+	switch index {
+	case "":
+		return fmt.Sprintf(`"%s.%s"`, cc.Type, fieldName)
+	case "i":
+		return fmt.Sprintf(`fmt.Sprintf("%s.%s[%%d]", i)`, cc.Type, fieldName)
+	default:
+		return fmt.Sprintf(`"%s.%s[%s]"`, cc.Type, fieldName, index)
+	}
+}
+
+// validateCall returns the code calling the validate method (generated
+// or user written), or an empty string if [ta] has none
+func validateCall(ta an.Struct, cc gen.Context) string {
+	if ta.Validate != nil { // user written
+		return hookCall(*ta.Validate, ta, cc)
+	}
+	if !ta.HasConstraints() {
+		return ""
+	}
+	var args []string
+	for _, arg := range userArguments(ta) {
+		args = append(args, arg.variableName)
+	}
+	return fmt.Sprintf(`if err := %s.validate(%s); err != nil {
+		%s
+	}`, cc.ObjectVar, strings.Join(args, ", "), cc.ErrReturn(gen.ErrVariable("err")))
+}
+
+// constraintChecks returns the checks for [cs], applied
+// to [value], with [path] used in the error.
+func constraintChecks(cs an.Constraints, value, path gen.Expression, objectVar string) string {
+	var (
+		conditions  []string
+		constraints []string
+	)
+	if cs.NonZero {
+		conditions = append(conditions, fmt.Sprintf("%s == 0", value))
+		constraints = append(constraints, "non zero")
+	}
+	if cs.Min != nil {
+		conditions = append(conditions, fmt.Sprintf("%s < %s", value, cs.Min.ExactString()))
+		constraints = append(constraints, ">= "+cs.Min.ExactString())
+	}
+	if cs.Max != nil {
+		conditions = append(conditions, fmt.Sprintf("%s > %s", value, cs.Max.ExactString()))
+		constraints = append(constraints, "<= "+cs.Max.ExactString())
+	}
+	if len(cs.OneOf) != 0 {
+		var (
+			cond  []string
+			names []string
+		)
+		for _, v := range cs.OneOf {
+			cond = append(cond, fmt.Sprintf("%s != %s", value, v.ExactString()))
+			names = append(names, v.ExactString())
+		}
+		conditions = append(conditions, strings.Join(cond, " && "))
+		constraints = append(constraints, "one of "+strings.Join(names, ", "))
+	}
+	if !cs.LessThan.IsEmpty() {
+		conditions = append(conditions, fmt.Sprintf("int(%s) >= int(%s)", value, cs.LessThan.Code(objectVar)))
+		constraints = append(constraints, "< "+cs.LessThan.Source)
+	}
+
+	code := make([]string, len(conditions))
+	for i, condition := range conditions {
+		code[i] = fmt.Sprintf(`if %s {
+			return validationError{path: %s, constraint: %q, value: %s}
+		}`, condition, path, constraints[i], value)
+	}
+	return strings.Join(code, "\n")
+}
+
+// validateForTable returns the validate method for [ta],
+// or nil if [ta] has no constraints.
+func validateForTable(ta an.Struct) []gen.Declaration {
+	if !ta.HasConstraints() {
+		return nil
+	}
+	origin := ta.Origin().(*types.Named)
+	typeName := origin.Obj().Name()
+	const objectVar = "item"
+
+	var checks []string
+	for _, field := range ta.Fields {
+		cs := field.Constraints
+		if cs.IsEmpty() {
+			continue
+		}
+		target := fmt.Sprintf("%s.%s", objectVar, field.Name)
+		switch field.Type.(type) {
+		case an.Slice, an.Array:
+			checks = append(checks, fmt.Sprintf(`for i, v := range %s {
+				%s
+			}`, target, constraintChecks(cs, "v", fmt.Sprintf(`fmt.Sprintf("%s.%s[%%d]", i)`, typeName, field.Name), objectVar)))
+		default:
+			checks = append(checks, constraintChecks(cs, target, fmt.Sprintf(`"%s.%s"`, typeName, field.Name), objectVar))
+		}
+	}
+
+	var args []string
+	for _, arg := range userArguments(ta) {
+		args = append(args, arg.asSignature())
+	}
+
+	return []gen.Declaration{{
+		ID:     typeName + ".validate",
+		Origin: origin,
+		Content: fmt.Sprintf(`// validate checks the constraints declared on the fields.
+		// It is called at the end of the parsing function.
+		func (%s *%s) validate(%s) error {
+			%s
+			return nil
+		}
+		`, objectVar, typeName, strings.Join(args, ", "), strings.Join(checks, "\n")),
+	}}
+}
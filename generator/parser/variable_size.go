@@ -32,8 +32,8 @@ func parserForStructTo(field an.Field, cc *gen.Context, target string) string {
 		%s
 		`,
 		vars,
-		target, readTarget, gen.ParseFunctionName(gen.Name(field.Type)), cc.Slice, start, args,
-		cc.ErrReturn(gen.ErrVariable("err")),
+		target, readTarget, parseFunction(field.Type), cc.Slice, start, args,
+		cc.ErrReturn(nestedErr(field.Type, fieldPath(*cc, field.Name, ""))),
 		updateOffset,
 	)
 }
@@ -48,8 +48,20 @@ func parserForVariableSize(field an.Field, parent an.Struct, cc *gen.Context) st
 		return parserForOffset(field, parent, cc)
 	case an.Union:
 		return parserForUnion(field, cc)
-	case an.Struct:
+	case an.Struct, an.Codec:
 		return parserForStructTo(field, cc, cc.Selector(field.Name))
+	case an.Array:
+		return parserForArray(field, cc)
+	case an.VarInt:
+		return parserForVarInt(field, cc)
+	case an.Compressed:
+		return parserForCompressed(field, cc)
+	case an.Padding:
+		return parserForPadding(field, cc)
+	case an.Map:
+		return parserForMap(field, cc)
+	case an.String:
+		return parserForString(field, cc)
 	}
 	return ""
 }
@@ -97,28 +109,49 @@ func parserForOpaque(field an.Field, parent an.Struct, cc *gen.Context) string {
 //     and use mustParse on each element
 //   - elements have a variable length : we have to check the length at each iteration
 //   - as an optimization, we special case raw bytes (see [Slice.IsRawData])
+//   - bit-stream arrays are unpacked in a dedicated function
 //   - slice of offsets are handled is in dedicated function
+//   - elements whose size only depends on arguments are handled
+//     as fixed size elements, with a size computed once
 //   - opaque types, whose interpretation is defered are represented by an [an.Opaque] type,
 //     and handled in a separate function
 func parserForSlice(field an.Field, cc *gen.Context) string {
-	sl := field.Type.(an.Slice)
+	return parserForSliceTo(field.Type.(an.Slice), field, cc, cc.Selector(field.Name))
+}
+
+// parserForSliceTo parses the slice [sl] described by [field], storing it in [target]
+func parserForSliceTo(sl an.Slice, field an.Field, cc *gen.Context, target string) string {
 	// no matter the kind of element, resolve the count
 	countExpr, countCode := codeForSliceCount(sl, field.Name, cc)
 
 	codes := []string{countCode}
 
-	if sl.IsRawData() { // special case for bytes data
+	if offset, isOffset := sl.Elem.(an.Offset); isOffset && !sl.ByteWidth.IsEmpty() { // offsets with runtime size
+		codes = append(codes, byteWidthDefinition(sl, *cc, countExpr, offset.Size))
+		codes = append(codes, parserForSliceOfOffsets(offset, cc, countExpr, field, target, true))
+	} else if !sl.ByteWidth.IsEmpty() { // integers with runtime size
+		codes = append(codes, parserForSliceByteWidth(sl, cc, countExpr, target))
+	} else if sl.IsBitStream() { // packed elements
+		codes = append(codes, parserForSliceBitStream(sl, cc, countExpr, target))
+	} else if sl.IsRawData() { // special case for bytes data
 		// adjust the start offset if needed
 		if sl.SubsliceStart == an.AtStart { // do not use the current offset as start
 			cc.Offset = gen.NewOffset(cc.Offset.Name, 0)
 		}
-		codes = append(codes, parserForSliceBytes(sl, cc, countExpr, field.Name))
+		codes = append(codes, parserForSliceBytes(sl, cc, countExpr, target))
+	} else if _, isVarInt := sl.Elem.(an.VarInt); isVarInt { // variable-length numbers
+		codes = append(codes, parserForSliceVarInt(sl, cc, countExpr, target))
 	} else if offset, isOffset := sl.Elem.(an.Offset); isOffset { // special case for slice of offsets
-		codes = append(codes, parserForSliceOfOffsets(offset, cc, countExpr, field))
+		codes = append(codes, parserForSliceOfOffsets(offset, cc, countExpr, field, target, false))
 	} else if _, isFixedSize := sl.Elem.IsFixedSize(); isFixedSize { // else, check for fixed size elements
-		codes = append(codes, parserForSliceFixedSizeElement(sl, cc, countExpr, field.Name))
+		codes = append(codes, parserForSliceFixedSizeElement(sl, field, cc, countExpr, target))
+		if check := sortedCheck(sl, *cc, field.Name, target); check != "" {
+			codes = append(codes, check)
+		}
+	} else if st, isStruct := sl.Elem.(an.Struct); isStruct && st.IsSizedByArguments() { // uniform size, computed once
+		codes = append(codes, parserForSliceSizedByArguments(st, cc, countExpr, field, target))
 	} else {
-		codes = append(codes, parserForSliceVariableSizeElement(sl, cc, countExpr, field))
+		codes = append(codes, parserForSliceVariableSizeElement(sl, cc, countExpr, field, target))
 	}
 
 	return strings.Join(codes, "\n")
@@ -129,11 +162,17 @@ func codeForSliceCount(sl an.Slice, fieldName string, cc *gen.Context) (countVar
 	switch sl.Count {
 	case an.NoLength: // the length is provided as an external variable
 		countVar = externalCountVariable(fieldName)
-	case an.FirstUint16, an.FirstUint32: // the length is at the start of the array
+	case an.FirstUint8, an.FirstUint16, an.FirstUint24, an.FirstUint32: // the length is at the start of the array
 		countVar = arrayCountName(cc.Selector(fieldName))
 	case an.ComputedField:
 		countVar = "arrayLength"
 		statements = append(statements, fmt.Sprintf("%s := int(%s)", countVar, cc.Selector(sl.CountExpr)))
+	case an.FirstUIntBase128: // the length is at the start of the array, with a variable size
+		countVar = arrayCountName(cc.Selector(fieldName))
+		statements = append(statements, codeForVarIntCount(an.UIntBase128, countVar, cc))
+	case an.First255UInt16:
+		countVar = arrayCountName(cc.Selector(fieldName))
+		statements = append(statements, codeForVarIntCount(an.U255UInt16, countVar, cc))
 	case an.ToEnd, an.ToComputedField:
 		// count is ignored in this case
 	}
@@ -141,8 +180,7 @@ func codeForSliceCount(sl an.Slice, fieldName string, cc *gen.Context) (countVar
 	return countVar, strings.Join(statements, "\n")
 }
 
-func parserForSliceBytes(sl an.Slice, cc *gen.Context, count gen.Expression, fieldName string) string {
-	target := cc.Selector(fieldName)
+func parserForSliceBytes(sl an.Slice, cc *gen.Context, count gen.Expression, target string) string {
 	start := cc.Offset.Value()
 	// special case for ToEnd : do not use an intermediate variable
 	if sl.Count == an.ToEnd {
@@ -193,8 +231,7 @@ func parserForSliceBytes(sl an.Slice, cc *gen.Context, count gen.Expression, fie
 //		out[i] = mustParseMorxChain(data[])
 //	}
 //	n += arrayLength * size
-func parserForSliceFixedSizeElement(sl an.Slice, cc *gen.Context, count gen.Expression, fieldName string) string {
-	target := cc.Selector(fieldName)
+func parserForSliceFixedSizeElement(sl an.Slice, field an.Field, cc *gen.Context, count gen.Expression, target string) string {
 	out := []string{""}
 
 	// step 1 : check the expected length
@@ -209,7 +246,16 @@ func parserForSliceFixedSizeElement(sl an.Slice, cc *gen.Context, count gen.Expr
 	// temporarily changing the offset
 	startOffset := cc.Offset
 	cc.Offset = gen.NewOffsetDynamic(cc.Offset.WithAffine("i", elementSize))
-	loopBody := mustParser(sl.Elem, *cc, fmt.Sprintf("%s[i]", cc.Selector(fieldName)))
+	var loopBody string
+	if an.IsFallible(sl.Elem) {
+		loopBody = parserChecked(sl.Elem, field, *cc, fmt.Sprintf("%s[i]", target))
+	} else {
+		loopBody = mustParser(sl.Elem, *cc, fmt.Sprintf("%s[i]", target))
+	}
+	if ba, isBasic := sl.Elem.(an.Basic); isBasic && an.HasValueChecks(ba) {
+		_, fieldName, _ := strings.Cut(target, ".")
+		loopBody += "\n" + valueCheck(ba, *cc, fmt.Sprintf("%s[i]", target), fieldName)
+	}
 	out = append(out, fmt.Sprintf(`for i := range %s {
 		%s
 	}`, target, loopBody))
@@ -222,6 +268,60 @@ func parserForSliceFixedSizeElement(sl an.Slice, cc *gen.Context, count gen.Expr
 	return strings.Join(out, "\n")
 }
 
+// The field is a slice of n-bit elements, where n is only known at run time.
+// The generated code will look like
+//
+//	bitWidth := <expr, with int operands>
+//	if bitWidth < 1 || bitWidth > 32 {
+//		return err
+//	}
+//	byteLength := (arrayLength*bitWidth + 7) / 8
+//	if len(data) < n + byteLength {
+//		return err
+//	}
+//	out = make([]uint32, arrayLength)
+//	for i := range out {
+//		<unpack the bits of out[i]>
+//	}
+//	n += byteLength
+func parserForSliceBitStream(sl an.Slice, cc *gen.Context, count gen.Expression, target string) string {
+	elemName := gen.Name(sl.Elem)
+	elemSize, _ := sl.Elem.IsFixedSize()
+	start := cc.Offset.Value()
+
+	errWidth := cc.ErrReturn(gen.ErrFormated(`"invalid bit width %d", bitWidth`))
+	errLength := cc.ErrReturn(gen.ErrFormated(fmt.Sprintf(`"EOF: expected length: %%d, got %%d", %s, L`, cc.Offset.WithAffine("byteLength", 1))))
+
+	out := fmt.Sprintf(`bitWidth := %s
+		if bitWidth < 1 || bitWidth > %d {
+			%s
+		}
+		byteLength := (%s*bitWidth + 7) / 8
+		if L := len(%s); L < %s {
+			%s
+		}
+		%s = make([]%s, %s) // allocation guarded by the previous check
+		for i := range %s {
+			var v %s
+			for b := i * bitWidth; b < (i+1)*bitWidth; b++ {
+				v = v<<1 | %s(%s[%s]>>(7-b%%8))&1
+			}
+			%s[i] = v
+		}
+		`, sl.BitWidth.IntCode(cc.ObjectVar),
+		8*elemSize, errWidth,
+		count,
+		cc.Slice, cc.Offset.WithAffine("byteLength", 1), errLength,
+		target, elemName, count,
+		target,
+		elemName,
+		elemName, cc.Slice, gen.ArrayOffset(start, "b/8", 1),
+		target,
+	)
+
+	return out + cc.Offset.UpdateStatementDynamic("byteLength")
+}
+
 // The field is a slice of structs, whose size is only known at run time
 // The generated code will look like
 //
@@ -235,7 +335,7 @@ func parserForSliceFixedSizeElement(sl an.Slice, cc *gen.Context, count gen.Expr
 //		offset += read
 //	}
 //	n = offset
-func parserForSliceVariableSizeElement(sl an.Slice, cc *gen.Context, count gen.Expression, field an.Field) string {
+func parserForSliceVariableSizeElement(sl an.Slice, cc *gen.Context, count gen.Expression, field an.Field, target string) string {
 	// if start is a constant, we have to use an additional variable
 
 	args := resolveSliceArgument(field.Type, *cc)
@@ -256,13 +356,58 @@ func parserForSliceVariableSizeElement(sl an.Slice, cc *gen.Context, count gen.E
 		%s`,
 		cc.Offset.Value(),
 		count,
-		gen.ParseFunctionName(gen.Name(sl.Elem)), cc.Slice, args,
-		cc.ErrReturn(gen.ErrVariable("err")),
-		cc.Selector(field.Name), cc.Selector(field.Name),
+		parseFunction(sl.Elem), cc.Slice, args,
+		cc.ErrReturn(nestedErr(sl.Elem, fieldPath(*cc, field.Name, "i"))),
+		target, target,
 		cc.Offset.SetStatement("offset"),
 	)
 }
 
+// ------------------------- arrays -------------------------
+
+// The field is an array of structs without static size
+// (for instance with hooks), parsed one by one.
+// The generated code will look like
+//
+//	offset := n
+//	for i := range item.array {
+//		elem, read, err := parseElem(data[offset:])
+//		if err != nil {
+//			return nil, err
+//		}
+//		item.array[i] = elem
+//		offset += read
+//	}
+//	n = offset
+func parserForArray(field an.Field, cc *gen.Context) string {
+	ar := field.Type.(an.Array)
+	args := resolveSliceArgument(ar, *cc)
+	args += resolveArguments(cc.ObjectVar, field.ArgumentsProvidedByFields, requiredArgs(ar.Elem, field.Name))
+	target := cc.Selector(field.Name)
+	updateOffset := cc.Offset.SetStatement("offset")
+	if cc.IgnoreUpdateOffset {
+		updateOffset = ""
+	}
+	return fmt.Sprintf(`
+		offset := %s
+		for i := range %s {
+			elem, read, err := %s(%s[offset:], %s)
+			if err != nil {
+				%s
+			}
+			%s[i] = elem
+			offset += read
+		}
+		%s`,
+		cc.Offset.Value(),
+		target,
+		parseFunction(ar.Elem), cc.Slice, args,
+		cc.ErrReturn(nestedErr(ar.Elem, fieldPath(*cc, field.Name, "i"))),
+		target,
+		updateOffset,
+	)
+}
+
 // ------------------------ Offsets ------------------------
 
 func parserForOffset(fi an.Field, parent an.Struct, cc *gen.Context) string {
@@ -291,7 +436,7 @@ func parserForOffset(fi an.Field, parent an.Struct, cc *gen.Context) string {
 	lengthCheck := lengthCheck(*cc, offsetVarName)
 
 	// Step 5 - finally delegate to the target parser
-	savedOffset := cc.Offset
+	savedOffset, savedIgnore := cc.Offset, cc.IgnoreUpdateOffset
 	cc.Offset = gen.NewOffsetDynamic(offsetVarName)
 	cc.IgnoreUpdateOffset = true
 
@@ -302,6 +447,7 @@ func parserForOffset(fi an.Field, parent an.Struct, cc *gen.Context) string {
 		ArgumentsProvidedByFields: fi.ArgumentsProvidedByFields,
 		UnionTag:                  fi.UnionTag,
 		OffsetRelativeTo:          fi.OffsetRelativeTo,
+		UnwrapFlag:                fi.UnwrapFlag,
 	}
 	if of.IsPointer {
 		readTarget = parserForStructTo(targetField, cc, tmpVarName)
@@ -312,6 +458,7 @@ func parserForOffset(fi an.Field, parent an.Struct, cc *gen.Context) string {
 	// restore value
 	cc.Slice = savedSlice
 	cc.Offset = savedOffset
+	cc.IgnoreUpdateOffset = savedIgnore
 
 	return fmt.Sprintf(` 
 	if %s != 0 { // ignore null offset
@@ -342,29 +489,51 @@ func parserForOffset(fi an.Field, parent an.Struct, cc *gen.Context) string {
 //		}
 //		elems[i] = parseElemType(src[offset:])
 //	}
-func parserForSliceOfOffsets(of an.Offset, cc *gen.Context, count gen.Expression, fi an.Field) string {
-	target := cc.Selector(fi.Name)
+//
+// For the records of maps, the non null elements are appended instead.
+//
+// If [runtimeWidth] is true, the offsets are stored using "width" bytes,
+// which must have been defined by the caller.
+func parserForSliceOfOffsets(of an.Offset, cc *gen.Context, count gen.Expression, fi an.Field, target string, runtimeWidth bool) string {
 	out := []string{""}
 
 	// step 1 : check the expected length
 	elementSize := of.Size
-	out = append(out, affineLengthCheckAt(*cc, count, elementSize))
+	elementOffset, byteLength := cc.Offset.WithAffine("i", elementSize), fmt.Sprintf("%s * %d", count, elementSize)
+	if runtimeWidth {
+		elementOffset, byteLength = cc.Offset.WithAffine("i*width", 1), count+"*width"
+		out = append(out, lengthCheck(*cc, cc.Offset.WithAffine(byteLength, 1)))
+	} else {
+		out = append(out, affineLengthCheckAt(*cc, count, elementSize))
+	}
 
 	// step 2 : allocate the slice of offsets target - it is garded by the check above
-	out = append(out, fmt.Sprintf("%s = make([]%s, %s) // allocation guarded by the previous check",
-		target, gen.Name(of.Target), count))
+	// the records of maps are appended, so that null offsets are skipped
+	_, isMap := fi.Type.(an.Map)
+	loop := "i := range " + target
+	if isMap {
+		out = append(out, fmt.Sprintf("%s = make([]%s, 0, %s) // allocation guarded by the previous check",
+			target, gen.Name(of.Target), count))
+		loop = fmt.Sprintf("i := 0; i < %s; i++", count)
+	} else {
+		out = append(out, fmt.Sprintf("%s = make([]%s, %s) // allocation guarded by the previous check",
+			target, gen.Name(of.Target), count))
+	}
 
 	// step 3 : loop to parse every elements,
 	// temporarily changing the offset
 	startOffset := cc.Offset
-	cc.Offset = gen.NewOffsetDynamic(cc.Offset.WithAffine("i", elementSize))
+	cc.Offset = gen.NewOffsetDynamic(elementOffset)
 
 	args := resolveSliceArgument(of.Target, *cc)
 	args += resolveArguments(cc.ObjectVar, fi.ArgumentsProvidedByFields, requiredArgs(of.Target, fi.Name))
 
 	// Loop body :
 	// Step 1 - read the offset value
-	readOffset := readBasicTypeAt(*cc, elementSize)
+	readOffset := fmt.Sprintf("offset := int(%s)", readBasicTypeAt(*cc, elementSize))
+	if runtimeWidth {
+		readOffset = "var offset int\n" + widthSwitch(*cc, cc.Offset.Value(), elementSize, "offset", "int")
+	}
 	// Step 2 - adjust the source slice
 	savedSlice := cc.Slice
 	if fi.OffsetRelativeTo == an.Parent {
@@ -375,39 +544,50 @@ func parserForSliceOfOffsets(of an.Offset, cc *gen.Context, count gen.Expression
 	// Step 3 - check the length for the pointed value
 	check := lengthCheck(*cc, "offset")
 	// Step 4 - finally delegate to the target parser
-	targetParse := fmt.Sprintf("%s[i], _, err = %s(%s[offset:], %s)", target, gen.ParseFunctionName(gen.Name(of.Target)), cc.Slice, args)
+	targetParse := fmt.Sprintf("%s[i], _, err = %s(%s[offset:], %s)", target, parseFunction(of.Target), cc.Slice, args)
+	store := ""
+	if isMap {
+		targetParse = fmt.Sprintf("record, _, err := %s(%s[offset:], %s)", parseFunction(of.Target), cc.Slice, args)
+		store = fmt.Sprintf("%s = append(%s, record)", target, target)
+	} else {
+		targetParse = "var err error\n" + targetParse
+	}
 
-	out = append(out, fmt.Sprintf(`for i := range %s {
-		offset := int(%s)
+	out = append(out, fmt.Sprintf(`for %s {
+		%s
 		// ignore null offsets 
 		if offset == 0 {
 			continue
 		}
 		
 		%s
-		var err error
 		%s
 		if err != nil {
 			%s
 		}
-	}`, target,
+		%s
+	}`, loop,
 		readOffset,
 		check,
 		targetParse,
-		cc.ErrReturn(gen.ErrVariable("err"))))
+		cc.ErrReturn(nestedErr(of.Target, fieldPath(*cc, fi.Name, "i"))),
+		store))
 
 	// step 5 : update the offset
 	cc.Slice = savedSlice
 	cc.Offset = startOffset
 	out = append(out,
-		cc.Offset.UpdateStatementDynamic(fmt.Sprintf("%s * %d", count, elementSize)))
+		cc.Offset.UpdateStatementDynamic(byteLength))
 
 	return strings.Join(out, "\n")
 }
 
 // -- unions --
 
-func unionCases(u an.Union, cc *gen.Context, providedArguments []an.ProvidedArgument, target string) []string {
+// unionCases returns the cases of the switch on the union tag.
+// Wrapper members are parsed, and their inner value is stored in [target],
+// setting [unwrapFlag] to true, if not empty.
+func unionCases(u an.Union, cc *gen.Context, providedArguments []an.ProvidedArgument, target, unwrapFlag string) []string {
 	start := cc.Offset.Value()
 	flags := u.UnionTag.TagsCode()
 	var cases []string
@@ -415,6 +595,24 @@ func unionCases(u an.Union, cc *gen.Context, providedArguments []an.ProvidedArgu
 		member := u.Members[i]
 		args := resolveSliceArgument(member, *cc)
 		args += resolveArguments(cc.ObjectVar, providedArguments, requiredArgs(member, target))
+		if member.Unwrap != "" {
+			setFlag := ""
+			if unwrapFlag != "" {
+				setFlag = unwrapFlag + " = true"
+			}
+			cases = append(cases, fmt.Sprintf(`case %s :
+			var wrapper %s
+			wrapper, read, err = %s(%s[%s:], %s)
+			%s = wrapper.%s
+			%s`,
+				flag,
+				gen.Name(member),
+				gen.ParseFunctionName(gen.Name(member)), cc.Slice, start, args,
+				target, member.Unwrap,
+				setFlag,
+			))
+			continue
+		}
 		cases = append(cases, fmt.Sprintf(`case %s :
 		%s, read, err = %s(%s[%s:], %s)`,
 			flag,
@@ -427,11 +625,13 @@ func unionCases(u an.Union, cc *gen.Context, providedArguments []an.ProvidedArgu
 
 func standaloneUnionBody(u an.Union, cc *gen.Context, cases []string) string {
 	// steps :
-	// 	1 : check the length for the format tag
+	// 	1 : check the length up to the format tag (included)
 	//	2 : read the format tag
 	//	3 : defer to the corresponding member parsing function
 	scheme := u.UnionTag.(an.UnionTagImplicit)
 	tagSize, _ := scheme.Tag.IsFixedSize()
+	tagContext := *cc
+	tagContext.Offset.Increment(scheme.Offset)
 	return fmt.Sprintf(`
 			%s
 			format := %s(%s)
@@ -448,8 +648,8 @@ func standaloneUnionBody(u an.Union, cc *gen.Context, cases []string) string {
 				%s
 			}
 			`,
-		staticLengthCheckAt(*cc, tagSize),
-		gen.Name(scheme.Tag), readBasicTypeAt(*cc, tagSize),
+		staticLengthCheckAt(tagContext, tagSize),
+		gen.Name(scheme.Tag), readBasicTypeAt(tagContext, tagSize),
 		strings.Join(cases, "\n"),
 		gen.Name(u),
 		cc.ErrReturn(gen.ErrVariable("err")),
@@ -459,7 +659,11 @@ func standaloneUnionBody(u an.Union, cc *gen.Context, cases []string) string {
 func parserForUnion(field an.Field, cc *gen.Context) string {
 	u := field.Type.(an.Union)
 
-	cases := unionCases(u, cc, field.ArgumentsProvidedByFields, cc.Selector(field.Name))
+	unwrapFlag := ""
+	if field.UnwrapFlag != "" {
+		unwrapFlag = cc.Selector(field.UnwrapFlag)
+	}
+	cases := unionCases(u, cc, field.ArgumentsProvidedByFields, cc.Selector(field.Name), unwrapFlag)
 
 	var code string
 	switch scheme := u.UnionTag.(type) {
@@ -481,7 +685,7 @@ func parserForUnion(field an.Field, cc *gen.Context) string {
 			strings.Join(cases, "\n"),
 			gen.Name(u),
 			kindVariable,
-			cc.ErrReturn(gen.ErrVariable("err")),
+			cc.ErrReturn(nestedErr(u, fieldPath(*cc, field.Name, ""))),
 		)
 	case an.UnionTagImplicit:
 		// defed to the generated standalone function
@@ -497,7 +701,7 @@ func parserForUnion(field an.Field, cc *gen.Context) string {
 			%s 
 		}
  		`, cc.Selector(field.Name), gen.ParseFunctionName(gen.Name(field.Type)), cc.Slice, cc.Offset.Value(), args,
-			cc.ErrReturn(gen.ErrVariable("err")))
+			cc.ErrReturn(nestedErr(u, fieldPath(*cc, field.Name, ""))))
 	default:
 		panic("exhaustive type switch")
 	}
@@ -0,0 +1,266 @@
+package parser
+
+import (
+	"fmt"
+
+	an "github.com/benoitkugler/binarygen/analysis"
+	gen "github.com/benoitkugler/binarygen/generator"
+)
+
+// variable-length integers are decoded by helper functions,
+// which are only added to the output when used
+
+// varIntHelpers are sorted so that a declaration is listed
+// before the declarations it depends on
+var varIntHelpers = []gen.Declaration{
+	{
+		ID: "readUIntBase128",
+		Content: `// readUIntBase128 decodes a WOFF2 UIntBase128, returning the number of bytes read.
+		func readUIntBase128(src []byte) (uint32, int, error) {
+			var accum uint32
+			for i := 0; i < 5; i++ {
+				if len(src) <= i {
+					return 0, 0, fmt.Errorf("EOF: expected length: %d, got %d", i+1, len(src))
+				}
+				dataByte := src[i]
+				if i == 0 && dataByte == 0x80 {
+					return 0, 0, varIntError{encoding: "UIntBase128", reason: "leading zeros"}
+				}
+				// if any of the top 7 bits are set, << 7 would overflow
+				if accum&0xFE000000 != 0 {
+					return 0, 0, varIntError{encoding: "UIntBase128", reason: "overflow"}
+				}
+				accum = accum<<7 | uint32(dataByte&0x7F)
+				if dataByte&0x80 == 0 {
+					return accum, i + 1, nil
+				}
+			}
+			return 0, 0, varIntError{encoding: "UIntBase128", reason: "more than 5 bytes"}
+		}
+		`,
+	},
+	{
+		ID: "read255UInt16",
+		Content: `// read255UInt16 decodes a WOFF2 255UInt16, returning the number of bytes read.
+		func read255UInt16(src []byte) (uint16, int, error) {
+			const (
+				oneMoreByteCode1 = 255
+				oneMoreByteCode2 = 254
+				wordCode         = 253
+				lowestUCode      = 253
+			)
+			if len(src) < 1 {
+				return 0, 0, fmt.Errorf("EOF: expected length: 1, got %d", len(src))
+			}
+			code := src[0]
+			switch code {
+			case wordCode:
+				if len(src) < 3 {
+					return 0, 0, fmt.Errorf("EOF: expected length: 3, got %d", len(src))
+				}
+				value := uint16(src[1])<<8 | uint16(src[2])
+				if value < 2*lowestUCode+256 {
+					return 0, 0, varIntError{encoding: "255UInt16", reason: "non canonical encoding"}
+				}
+				return value, 3, nil
+			case oneMoreByteCode1, oneMoreByteCode2:
+				if len(src) < 2 {
+					return 0, 0, fmt.Errorf("EOF: expected length: 2, got %d", len(src))
+				}
+				if code == oneMoreByteCode2 {
+					return uint16(src[1]) + 2*lowestUCode, 2, nil
+				}
+				if src[1] >= lowestUCode {
+					return 0, 0, varIntError{encoding: "255UInt16", reason: "non canonical encoding"}
+				}
+				return uint16(src[1]) + lowestUCode, 2, nil
+			default:
+				return uint16(code), 1, nil
+			}
+		}
+		`,
+	},
+	{
+		ID: "readCFFReal",
+		Content: `// readCFFReal decodes a CFF DICT operand, either a real number or
+		// an integer, returning the number of bytes read.
+		func readCFFReal(src []byte) (float64, int, error) {
+			if len(src) < 1 || src[0] != 30 {
+				v, read, err := readCFFInteger(src)
+				return float64(v), read, err
+			}
+			var buf []byte
+			for i := 1; i < len(src); i++ {
+				for _, nibble := range [2]byte{src[i] >> 4, src[i] & 0xF} {
+					switch {
+					case nibble <= 9:
+						buf = append(buf, '0'+nibble)
+					case nibble == 0xa:
+						buf = append(buf, '.')
+					case nibble == 0xb:
+						buf = append(buf, 'E')
+					case nibble == 0xc:
+						buf = append(buf, 'E', '-')
+					case nibble == 0xe:
+						buf = append(buf, '-')
+					case nibble == 0xf:
+						v, err := strconv.ParseFloat(string(buf), 64)
+						if err != nil {
+							return 0, 0, varIntError{encoding: "CFFOperand", reason: "invalid real number " + string(buf)}
+						}
+						return v, i + 1, nil
+					default:
+						return 0, 0, varIntError{encoding: "CFFOperand", reason: "reserved nibble"}
+					}
+				}
+			}
+			return 0, 0, fmt.Errorf("EOF: unterminated real number")
+		}
+		`,
+	},
+	{
+		ID: "readCFFInteger",
+		Content: `// readCFFInteger decodes a CFF DICT integer operand, returning the number of bytes read.
+		func readCFFInteger(src []byte) (int32, int, error) {
+			if len(src) < 1 {
+				return 0, 0, fmt.Errorf("EOF: expected length: 1, got %d", len(src))
+			}
+			b0 := src[0]
+			switch {
+			case 32 <= b0 && b0 <= 246:
+				return int32(b0) - 139, 1, nil
+			case 247 <= b0 && b0 <= 254:
+				if len(src) < 2 {
+					return 0, 0, fmt.Errorf("EOF: expected length: 2, got %d", len(src))
+				}
+				if b0 <= 250 {
+					return (int32(b0)-247)*256 + int32(src[1]) + 108, 2, nil
+				}
+				return -(int32(b0)-251)*256 - int32(src[1]) - 108, 2, nil
+			case b0 == 28:
+				if len(src) < 3 {
+					return 0, 0, fmt.Errorf("EOF: expected length: 3, got %d", len(src))
+				}
+				return int32(int16(binary.BigEndian.Uint16(src[1:]))), 3, nil
+			case b0 == 29:
+				if len(src) < 5 {
+					return 0, 0, fmt.Errorf("EOF: expected length: 5, got %d", len(src))
+				}
+				return int32(binary.BigEndian.Uint32(src[1:])), 5, nil
+			case b0 == 30:
+				return 0, 0, varIntError{encoding: "CFFOperand", reason: "unexpected real number"}
+			default:
+				return 0, 0, varIntError{encoding: "CFFOperand", reason: fmt.Sprintf("invalid operand byte %d", b0)}
+			}
+		}
+		`,
+	},
+	{
+		ID: "varIntError",
+		Content: `// varIntError is returned when a variable-length number is
+		// invalid or not canonical
+		type varIntError struct {
+			encoding string
+			reason   string
+		}
+
+		func (err varIntError) Error() string {
+			return fmt.Sprintf("invalid %s: %s", err.encoding, err.reason)
+		}
+		`,
+	},
+}
+
+// varIntReader returns the name of the function decoding
+// the given encoding
+func varIntReader(encoding an.VarIntEncoding, isReal bool) string {
+	switch encoding {
+	case an.UIntBase128:
+		return "readUIntBase128"
+	case an.U255UInt16:
+		return "read255UInt16"
+	case an.CFFOperand:
+		if isReal {
+			return "readCFFReal"
+		}
+		return "readCFFInteger"
+	default:
+		panic("exhaustive switch")
+	}
+}
+
+// parserForVarInt decodes one number, and updates the offset
+func parserForVarInt(field an.Field, cc *gen.Context) string {
+	vi := field.Type.(an.VarInt)
+	code := fmt.Sprintf(`v, read, err := %s(%s[%s:])
+		if err != nil {
+			%s
+		}
+		%s = %s(v)
+		`, varIntReader(vi.Encoding, vi.IsReal()), cc.Slice, cc.Offset.Value(),
+		cc.ErrReturn(gen.ErrVariable("err")),
+		cc.Selector(field.Name), gen.Name(vi),
+	)
+	if cc.IgnoreUpdateOffset {
+		return code + "_ = read"
+	}
+	return code + cc.Offset.UpdateStatementDynamic("read")
+}
+
+// codeForVarIntCount decodes the number of elements of an array
+func codeForVarIntCount(encoding an.VarIntEncoding, countVar gen.Expression, cc *gen.Context) string {
+	return fmt.Sprintf(`count, read, err := %s(%s[%s:])
+		if err != nil {
+			%s
+		}
+		%s
+		%s := int(count)`, varIntReader(encoding, false), cc.Slice, cc.Offset.Value(),
+		cc.ErrReturn(gen.ErrVariable("err")),
+		cc.Offset.UpdateStatementDynamic("read"),
+		countVar,
+	)
+}
+
+// The field is a slice of variable-length numbers.
+// Since each element uses at least one byte,
+// the allocation may be guarded by a length check.
+// The generated code will look like
+//
+//	if len(data) < n + arrayLength {
+//		return err
+//	}
+//	out = make([]uint32, arrayLength)
+//	offset := n
+//	for i := range out {
+//		v, read, err := readUIntBase128(data[offset:])
+//		if err != nil {
+//			return err
+//		}
+//		out[i] = v
+//		offset += read
+//	}
+//	n = offset
+func parserForSliceVarInt(sl an.Slice, cc *gen.Context, count gen.Expression, target string) string {
+	vi := sl.Elem.(an.VarInt)
+	return fmt.Sprintf(`%s
+		%s = make([]%s, %s) // allocation guarded by the previous check
+		offset := %s
+		for i := range %s {
+			v, read, err := %s(%s[offset:])
+			if err != nil {
+				%s
+			}
+			%s[i] = %s(v)
+			offset += read
+		}
+		%s
+		`, affineLengthCheckAt(*cc, count, 1),
+		target, gen.Name(vi), count,
+		cc.Offset.Value(),
+		target,
+		varIntReader(vi.Encoding, vi.IsReal()), cc.Slice,
+		cc.ErrReturn(gen.ErrVariable("err")),
+		target, gen.Name(vi),
+		cc.Offset.SetStatement("offset"),
+	)
+}
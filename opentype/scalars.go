@@ -0,0 +1,173 @@
+// Package opentype provides the scalar types used in Opentype font files,
+// which are recognized by binarygen without custom constructors.
+//
+// The generated parsers call the Validate methods, and report
+// invalid values as errors.
+package opentype
+
+import (
+	"fmt"
+	"time"
+)
+
+// F2Dot14 is a signed 2.14 fixed point number,
+// stored in 2 bytes.
+type F2Dot14 int16
+
+// NewF2Dot14 returns the closest F2Dot14 to [f], which
+// is clamped to the range of valid values.
+func NewF2Dot14(f float32) F2Dot14 {
+	return F2Dot14(roundClamp(float64(f)*(1<<14), -1<<15, 1<<15-1))
+}
+
+// Float returns the number as a float
+func (f F2Dot14) Float() float32 { return float32(f) / (1 << 14) }
+
+func (f F2Dot14) String() string { return fmt.Sprintf("%g", f.Float()) }
+
+// Fixed is a signed 16.16 fixed point number,
+// stored in 4 bytes.
+type Fixed int32
+
+// NewFixed returns the closest Fixed to [f], which
+// is clamped to the range of valid values.
+func NewFixed(f float64) Fixed {
+	return Fixed(roundClamp(f*(1<<16), -1<<31, 1<<31-1))
+}
+
+// Float returns the number as a float
+func (f Fixed) Float() float64 { return float64(f) / (1 << 16) }
+
+func (f Fixed) String() string { return fmt.Sprintf("%g", f.Float()) }
+
+func roundClamp(f, min, max float64) float64 {
+	if f < 0 {
+		f -= 0.5
+	} else {
+		f += 0.5
+	}
+	if f < min {
+		return min
+	} else if f > max {
+		return max
+	}
+	return f
+}
+
+// Tag is a 4 bytes identifier, made of
+// printable ASCII characters.
+type Tag uint32
+
+// NewTag returns the tag for the 4 bytes string [s],
+// or an error if it is not valid.
+func NewTag(s string) (Tag, error) {
+	if len(s) != 4 {
+		return 0, fmt.Errorf("invalid tag %q: expected 4 bytes", s)
+	}
+	tag := Tag(uint32(s[0])<<24 | uint32(s[1])<<16 | uint32(s[2])<<8 | uint32(s[3]))
+	return tag, tag.Validate()
+}
+
+// MustNewTag is the same as [NewTag], but panics on invalid input.
+func MustNewTag(s string) Tag {
+	tag, err := NewTag(s)
+	if err != nil {
+		panic(err)
+	}
+	return tag
+}
+
+// Validate checks that the tag is made of printable ASCII characters
+// (0x20 to 0x7E), with spaces only used as trailing padding.
+func (t Tag) Validate() error {
+	hasSpace := false
+	for i, c := range t.bytes() {
+		if c < 0x20 || c > 0x7E {
+			return fmt.Errorf("invalid tag %q: byte %d is not printable", t.bytes(), i)
+		}
+		if c == ' ' {
+			hasSpace = true
+		} else if hasSpace {
+			return fmt.Errorf("invalid tag %q: spaces must be trailing", t.bytes())
+		}
+	}
+	return nil
+}
+
+func (t Tag) bytes() []byte {
+	return []byte{byte(t >> 24), byte(t >> 16), byte(t >> 8), byte(t)}
+}
+
+func (t Tag) String() string { return string(t.bytes()) }
+
+// LongDateTime is a date, as the number of seconds
+// since 12:00 midnight, January 1, 1904, UTC
+type LongDateTime int64
+
+// longDateTimeEpoch is the number of seconds between
+// the LongDateTime epoch and the Unix epoch
+const longDateTimeEpoch = 2082844800
+
+// NewLongDateTime returns the date for [t]
+func NewLongDateTime(t time.Time) LongDateTime {
+	return LongDateTime(t.Unix() + longDateTimeEpoch)
+}
+
+// Time returns the date as a UTC time
+func (ld LongDateTime) Time() time.Time {
+	return time.Unix(int64(ld)-longDateTimeEpoch, 0).UTC()
+}
+
+// Validate checks that the date is not before 1904, and that
+// its year has at most 4 digits.
+func (ld LongDateTime) Validate() error {
+	if ld < 0 || ld.Time().Year() > 9999 {
+		return fmt.Errorf("invalid date: %d seconds since 1904", int64(ld))
+	}
+	return nil
+}
+
+func (ld LongDateTime) String() string { return ld.Time().Format(time.RFC3339) }
+
+// Version16Dot16 is a version number, whose major part
+// is stored in the high 16 bits, and whose minor part
+// is stored in the low 16 bits, as in 0x00015000 for version 1.5
+type Version16Dot16 uint32
+
+// Major returns the major version
+func (v Version16Dot16) Major() uint16 { return uint16(v >> 16) }
+
+// Minor returns the minor version, as stored
+func (v Version16Dot16) Minor() uint16 { return uint16(v) }
+
+func (v Version16Dot16) String() string {
+	// the minor version is usually stored as a decimal digit
+	// in the high nibble
+	if minor := v.Minor(); minor&0xFFF == 0 && minor>>12 <= 9 {
+		return fmt.Sprintf("%d.%d", v.Major(), minor>>12)
+	}
+	return fmt.Sprintf("%d.0x%04x", v.Major(), v.Minor())
+}
+
+// GlyphID is the index of a glyph in a font
+type GlyphID uint16
+
+func (gid GlyphID) String() string { return fmt.Sprintf("gid%d", uint16(gid)) }
+
+// Offset16 is an offset stored in 2 bytes, where 0 is a null offset
+type Offset16 uint16
+
+// Offset24 is an offset stored in 3 bytes, where 0 is a null offset
+type Offset24 uint32
+
+// Offset32 is an offset stored in 4 bytes, where 0 is a null offset
+type Offset32 uint32
+
+// IsNull returns true for the null offset
+func (of Offset16) IsNull() bool { return of == 0 }
+
+// IsNull returns true for the null offset
+func (of Offset24) IsNull() bool { return of == 0 }
+
+// IsNull returns true for the null offset
+func (of Offset32) IsNull() bool { return of == 0 }
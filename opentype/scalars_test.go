@@ -0,0 +1,76 @@
+package opentype
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFixedPoint(t *testing.T) {
+	for _, test := range []struct {
+		raw      F2Dot14
+		expected float32
+	}{
+		{0x7fff, 1.999939},
+		{0x7000, 1.75},
+		{0x0001, 0.000061},
+		{0x0000, 0},
+		{-1, -0.000061},
+		{-0x4000, -1},
+		{-0x8000, -2},
+	} {
+		if got := test.raw.Float(); got-test.expected > 1e-6 || test.expected-got > 1e-6 {
+			t.Fatalf("for %d, expected %g, got %g", test.raw, test.expected, got)
+		}
+		if back := NewF2Dot14(test.raw.Float()); back != test.raw {
+			t.Fatalf("expected %d, got %d", test.raw, back)
+		}
+	}
+	if f := NewF2Dot14(3); f != 0x7fff {
+		t.Fatal(f)
+	}
+
+	if f := Fixed(-0x18000); f.Float() != -1.5 || NewFixed(-1.5) != f {
+		t.Fatal(f)
+	}
+}
+
+func TestTag(t *testing.T) {
+	tag := MustNewTag("OS/2")
+	if tag != 0x4f532f32 || tag.String() != "OS/2" {
+		t.Fatal(tag)
+	}
+	if tag := MustNewTag("cvt "); tag.Validate() != nil {
+		t.Fatal(tag)
+	}
+	for _, invalid := range []string{"", "glyf0", "a bc", "\x00abc"} {
+		if _, err := NewTag(invalid); err == nil {
+			t.Fatalf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestLongDateTime(t *testing.T) {
+	if date := LongDateTime(0).Time(); date != time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC) {
+		t.Fatal(date)
+	}
+	date := time.Date(2022, 5, 12, 8, 30, 0, 0, time.UTC)
+	if ld := NewLongDateTime(date); ld.Time() != date || ld.Validate() != nil {
+		t.Fatal(ld)
+	}
+	if err := LongDateTime(-1).Validate(); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestVersion(t *testing.T) {
+	for v, expected := range map[Version16Dot16]string{
+		0x00010000: "1.0",
+		0x00005000: "0.5",
+		0x00025000: "2.5",
+		0x00010001: "1.0x0001",
+	} {
+		if got := v.String(); got != expected {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
@@ -1,6 +1,13 @@
 package testpackage
 
-import "math"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
 
 type withFixedSize struct {
 	a, b, c int32
@@ -65,3 +72,72 @@ const _dummy3 flagNotVersion_ = 8
 func (WithChildArgument) parseCustomWithArg(_ []byte, arrayCount int, kind uint16, version uint16) (int, error) {
 	return 0, nil
 }
+
+// constructors may reject some values
+
+type markClass uint16
+
+func markClassFromUint(v uint16) (markClass, error) {
+	if v == 0xFFFF {
+		return 0, errors.New("reserved mark class")
+	}
+	return markClass(v), nil
+}
+
+func markClassToUint(mc markClass) uint16 { return uint16(mc) }
+
+type nameID uint16
+
+func (id *nameID) fromUint(v uint16) error {
+	if v >= 0x8000 {
+		return fmt.Errorf("reserved name ID %d", v)
+	}
+	*id = nameID(v)
+	return nil
+}
+
+func (id nameID) toUint() uint16 { return uint16(id) }
+
+// invalid or incomplete constructors are reported
+
+type readOnly uint8
+
+func readOnlyFromUint(v uint8) readOnly { return readOnly(v) }
+
+type badConstructor uint16
+
+func badConstructorFromUint(a, b uint16) badConstructor { return badConstructor(a + b) }
+
+// types from other packages may be registered with codecs
+
+// binarygen: codec type=time.Time parse=parseDate write=appendDate size=8
+// binarygen: codec type=math/big.Int parse=parseBigInt
+
+// parseDate reads a LONGDATETIME
+func parseDate(src []byte) (time.Time, int, error) {
+	if len(src) < 8 {
+		return time.Time{}, 0, fmt.Errorf("EOF: expected length: 8, got %d", len(src))
+	}
+	seconds := int64(binary.BigEndian.Uint64(src))
+	if seconds < 0 {
+		return time.Time{}, 0, errors.New("date before 1904")
+	}
+	return time.Unix(seconds-2082844800, 0).UTC(), 8, nil
+}
+
+func appendDate(dst []byte, date time.Time) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(date.Unix()+2082844800))
+	return append(dst, buf[:]...)
+}
+
+// parseBigInt reads an unsigned integer prefixed by its length
+func parseBigInt(src []byte) (big.Int, int, error) {
+	var out big.Int
+	if len(src) < 1 || len(src) < 1+int(src[0]) {
+		return out, 0, errors.New("EOF: invalid big integer")
+	}
+	L := int(src[0])
+	out.SetBytes(src[1 : 1+L])
+	return out, 1 + L, nil
+}
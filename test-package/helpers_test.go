@@ -0,0 +1,313 @@
+package testpackage
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"hash/adler32"
+	"hash/crc32"
+	"math"
+	"testing"
+)
+
+// checkError reports whether [err] matches [reason],
+// which is empty when no error is expected, "EOF" for a truncated input,
+// or the reason of a [varIntError]
+func checkError(err error, reason string) bool {
+	var vi varIntError
+	switch reason {
+	case "":
+		return err == nil
+	case "EOF":
+		return err != nil && !errors.As(err, &vi)
+	default:
+		return errors.As(err, &vi) && vi.reason == reason
+	}
+}
+
+func TestReadUIntBase128(t *testing.T) {
+	for _, test := range []struct {
+		src      []byte
+		expected uint32
+		read     int
+		reason   string
+	}{
+		{[]byte{0x3F}, 63, 1, ""},
+		{[]byte{0x81, 0x00, 0xFF}, 128, 2, ""},
+		{[]byte{0x8F, 0xFF, 0xFF, 0xFF, 0x7F}, math.MaxUint32, 5, ""},
+		{[]byte{0x80, 0x01}, 0, 0, "leading zeros"},
+		{[]byte{0x90, 0x80, 0x80, 0x80, 0x00}, 0, 0, "overflow"},
+		{[]byte{0x81, 0x80, 0x80, 0x80, 0x80, 0x00}, 0, 0, "more than 5 bytes"},
+		{[]byte{0x81}, 0, 0, "EOF"},
+		{nil, 0, 0, "EOF"},
+	} {
+		got, read, err := readUIntBase128(test.src)
+		if !checkError(err, test.reason) {
+			t.Fatalf("for %v, unexpected error %v", test.src, err)
+		}
+		if got != test.expected || read != test.read {
+			t.Fatalf("for %v, expected %d (%d bytes), got %d (%d bytes)", test.src, test.expected, test.read, got, read)
+		}
+	}
+}
+
+func TestRead255UInt16(t *testing.T) {
+	for _, test := range []struct {
+		src      []byte
+		expected uint16
+		read     int
+		reason   string
+	}{
+		{[]byte{252}, 252, 1, ""},
+		{[]byte{255, 0}, 253, 2, ""},
+		{[]byte{254, 0}, 506, 2, ""},
+		{[]byte{254, 255}, 761, 2, ""},
+		{[]byte{253, 0x02, 0xFA}, 762, 3, ""},
+		{[]byte{253, 0xFF, 0xFF}, 0xFFFF, 3, ""},
+		{[]byte{255, 253}, 0, 0, "non canonical encoding"},
+		{[]byte{253, 0x02, 0xF9}, 0, 0, "non canonical encoding"},
+		{[]byte{253, 0x00, 0x01}, 0, 0, "non canonical encoding"},
+		{[]byte{253, 0x02}, 0, 0, "EOF"},
+		{[]byte{254}, 0, 0, "EOF"},
+		{nil, 0, 0, "EOF"},
+	} {
+		got, read, err := read255UInt16(test.src)
+		if !checkError(err, test.reason) {
+			t.Fatalf("for %v, unexpected error %v", test.src, err)
+		}
+		if got != test.expected || read != test.read {
+			t.Fatalf("for %v, expected %d (%d bytes), got %d (%d bytes)", test.src, test.expected, test.read, got, read)
+		}
+	}
+}
+
+func TestReadCFFReal(t *testing.T) {
+	for _, test := range []struct {
+		src      []byte
+		expected float64
+		read     int
+		reason   string
+	}{
+		// examples of the CFF specification
+		{[]byte{0x1e, 0xe2, 0xa2, 0x5f}, -2.25, 4, ""},
+		{[]byte{0x1e, 0x0a, 0x14, 0x05, 0x41, 0xc3, 0xff}, 0.140541e-3, 7, ""},
+		{[]byte{0x1e, 0x1b, 0x2f}, 1e2, 3, ""},
+		{[]byte{0x1e, 0x1f, 0xff}, 1, 2, ""},
+		// integers
+		{[]byte{0x8b}, 0, 1, ""},
+		{[]byte{0xf7, 0x00}, 108, 2, ""},
+		{[]byte{0xfb, 0x00}, -108, 2, ""},
+		{[]byte{28, 0xFF, 0xFE}, -2, 3, ""},
+		{[]byte{0x1e, 0x1d, 0xff}, 0, 0, "reserved nibble"},
+		{[]byte{0x1e, 0xaa, 0xff}, 0, 0, "invalid real number .."},
+		{[]byte{0x1e, 0x12}, 0, 0, "EOF"},
+		{[]byte{31}, 0, 0, "invalid operand byte 31"},
+	} {
+		got, read, err := readCFFReal(test.src)
+		if !checkError(err, test.reason) {
+			t.Fatalf("for %v, unexpected error %v", test.src, err)
+		}
+		if math.Abs(got-test.expected) > 1e-12 || read != test.read {
+			t.Fatalf("for %v, expected %g (%d bytes), got %g (%d bytes)", test.src, test.expected, test.read, got, read)
+		}
+	}
+}
+
+func TestReadInflated(t *testing.T) {
+	for _, test := range []struct {
+		data     string
+		length   int
+		exceeded bool
+		err      bool
+	}{
+		{"abcd", 4, false, false},
+		{"", 0, false, false},
+		{"abcd", 5, false, true},
+		{"abcd", 3, true, true},
+		{"abcd", -1, false, true},
+	} {
+		out, err := readInflated(bytes.NewReader([]byte(test.data)), test.length, "zlib")
+		if (err != nil) != test.err {
+			t.Fatalf("for %q (%d), unexpected error %v", test.data, test.length, err)
+		}
+		var ce compressionError
+		if test.err && (!errors.As(err, &ce) || ce.exceeded != test.exceeded) {
+			t.Fatalf("for %q (%d), unexpected error %v", test.data, test.length, err)
+		}
+		if !test.err && string(out) != test.data {
+			t.Fatalf("expected %q, got %q", test.data, out)
+		}
+	}
+
+	// the compressed length is returned
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(bytes.Repeat([]byte("data"), 100))
+	w.Close()
+	compressed := buf.Len()
+	buf.WriteString("trailing bytes")
+	out, read, err := inflateZlib(buf.Bytes(), 400)
+	if err != nil || len(out) != 400 || read != compressed {
+		t.Fatal(err, len(out), read, compressed)
+	}
+	if _, _, err = inflateZlib(buf.Bytes(), 399); err == nil {
+		t.Fatal("expected error for data exceeding the length")
+	}
+}
+
+func TestDecodeStrings(t *testing.T) {
+	for _, test := range []struct {
+		src      []byte
+		expected string
+	}{
+		{[]byte("abc"), "abc"},
+		{[]byte{0x80, 0x8E, 'a', 0xCA, 0xFF}, "\u00c4\u00e9a\u00a0\u02c7"},
+	} {
+		if got, _ := decodeMacRoman(test.src); got != test.expected {
+			t.Fatalf("for %v, expected %q, got %q", test.src, test.expected, got)
+		}
+	}
+
+	for _, test := range []struct {
+		src      []byte
+		expected string
+		index    int // of the error, or -1
+	}{
+		{[]byte{0, 'a', 0x00, 0xE9}, "aé", -1},
+		{[]byte{0xD8, 0x3D, 0xDE, 0x00, 0, 'b'}, "😀b", -1},
+		{nil, "", -1},
+		{[]byte{0, 'a', 0}, "", 2},
+		{[]byte{0, 'a', 0xD8, 0x3D}, "", 2},
+		{[]byte{0xD8, 0x3D, 0, 'a'}, "", 0},
+		{[]byte{0, 'a', 0xDE, 0x00}, "", 2},
+	} {
+		got, err := decodeUTF16BE(test.src)
+		var se stringError
+		if test.index == -1 && err != nil || test.index != -1 && (!errors.As(err, &se) || se.index != test.index) {
+			t.Fatalf("for %v, unexpected error %v", test.src, err)
+		}
+		if got != test.expected {
+			t.Fatalf("for %v, expected %q, got %q", test.src, test.expected, got)
+		}
+	}
+}
+
+func TestSignExtension(t *testing.T) {
+	for _, test := range []struct {
+		offset   [3]byte
+		small    byte
+		expected int
+	}{
+		{[3]byte{0x00, 0x00, 0x01}, 0x7F, 1},
+		{[3]byte{0x7F, 0xFF, 0xFF}, 0x00, 0x7FFFFF},
+		{[3]byte{0xFF, 0xFF, 0xFE}, 0xFF, -2},
+		{[3]byte{0x80, 0x00, 0x00}, 0x80, -0x800000},
+	} {
+		src := make([]byte, 18)
+		copy(src[4:], test.offset[:])
+		src[7] = test.small
+		src[15] = 0xFF // values[1], unsigned
+		item, _, err := ParseWithBinarySize(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.offset != test.expected || item.small != int16(int8(test.small)) {
+			t.Fatalf("for %v, expected %d, got %d", test.offset, test.expected, item.offset)
+		}
+		if item.values[1] != 0xFF0000 {
+			t.Fatal(item.values)
+		}
+	}
+}
+
+func TestBitfieldSignExtension(t *testing.T) {
+	for _, test := range []struct {
+		storage byte
+		shift   int8
+		scale   int16
+	}{
+		{0x00, 0, 0},
+		{0x71, 1, 7},
+		{0x8F, -1, -8},
+		{0xF8, -8, -1},
+	} {
+		item, _, err := ParseWithAdjacentBitfields([]byte{0, 0, 0, 0, test.storage})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.deltaShift != test.shift || item.deltaScale != test.scale {
+			t.Fatalf("for %#x, expected %d, %d, got %d, %d", test.storage, test.shift, test.scale, item.deltaShift, item.deltaScale)
+		}
+	}
+}
+
+func TestTableChecksums(t *testing.T) {
+	src := make([]byte, 20000)
+	for i := range src {
+		src[i] = 0xFF - byte(i%7)
+	}
+	item, _, err := ParseWithTableChecksums(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// reference implementations, with the checksum field zeroed
+	zeroed := func(field int) []byte {
+		data := append([]byte(nil), src...)
+		copy(data[field:], []byte{0, 0, 0, 0})
+		return data
+	}
+	if got, _ := item.computeSum(src); got != checksumOpenType(zeroed(2)) {
+		t.Fatalf("expected 0x%08x, got 0x%08x", checksumOpenType(zeroed(2)), got)
+	}
+	if got, _ := item.computeAdler(src); got != adler32.Checksum(zeroed(6)) {
+		t.Fatalf("expected 0x%08x, got 0x%08x", adler32.Checksum(zeroed(6)), got)
+	}
+	var other WithChecksums
+	if got, _ := other.computeCrc(src); got != crc32.ChecksumIEEE(zeroed(16)) {
+		t.Fatalf("expected 0x%08x, got 0x%08x", crc32.ChecksumIEEE(zeroed(16)), got)
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() {
+		item.computeSum(src)
+		item.computeAdler(src)
+		other.computeCrc(src)
+	}); allocs != 0 {
+		t.Fatalf("unexpected allocations: %f", allocs)
+	}
+}
+
+func TestNestedValidationPaths(t *testing.T) {
+	valid := func() []byte {
+		return []byte{
+			0, 2, 0, 1, 0, 1, 0, 2, 0, 2, // records
+			0, 1, 0, 0, // first
+			0, 1, 0, 0, 0, 2, 0, 3, // pair
+			0, 24, // nested offset
+			0, 1, 0, 16, 0, 1, 0, 0, 0, 3, 0, 0, // nested
+		}
+	}
+	if _, _, err := ParseWithConstrainedRecords(valid(), 10); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		index byte // in the valid data
+		value byte
+		path  string
+	}{
+		{9, 4, "WithConstrainedRecords.records[1].class"},
+		{13, 4, "WithConstrainedRecords.first.class"},
+		{19, 10, "WithConstrainedRecords.pair[1].glyph"},
+		{27, 1, "WithConstrainedRecords.nested.unitsPerEm"},
+		{35, 2, "WithConstrainedRecords.nested.classes[1]"},
+	} {
+		src := valid()
+		src[test.index] = test.value
+		_, _, err := ParseWithConstrainedRecords(src, 10)
+		var ve validationError
+		if !errors.As(err, &ve) || ve.path != test.path {
+			t.Fatalf("expected error for %s, got %v", test.path, err)
+		}
+	}
+}
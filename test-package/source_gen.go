@@ -1,12 +1,35 @@
 package testpackage
 
 import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"hash/adler32"
+	"hash/crc32"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/benoitkugler/binarygen/opentype"
 )
 
 // Code generated by binarygen from ../../test-package/source_src.go. DO NOT EDIT
 
+func (item *DeviceTableVariation) mustParse(src []byte) {
+	_ = src[5] // early bound checking
+	item.deltaSetOuterIndex = binary.BigEndian.Uint16(src[0:])
+	item.deltaSetInnerIndex = binary.BigEndian.Uint16(src[2:])
+	item.deltaFormat = binary.BigEndian.Uint16(src[4:])
+}
+
 func (item *ImplicitITF1) mustParse(src []byte) {
 	_ = src[6] // early bound checking
 	item.kind = binary.BigEndian.Uint16(src[0:])
@@ -37,6 +60,140 @@ func (item *ImplicitITF3) mustParse(src []byte) {
 	item.data[4] = binary.BigEndian.Uint64(src[34:])
 }
 
+func ParseCFFIndex(src []byte) (CFFIndex, int, error) {
+	var item CFFIndex
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading CFFIndex: "+"EOF: expected length: 2, got %d", L)
+	}
+	item.count = binary.BigEndian.Uint16(src[0:])
+
+	n += 2
+
+	hasOffSize := item.count != 0
+	{
+		expectedLength := 2
+		if hasOffSize {
+			expectedLength += 1
+		}
+		if L := len(src); L < expectedLength {
+			return item, 0, fmt.Errorf("reading CFFIndex: "+"EOF: expected length: %d, got %d", expectedLength, L)
+		}
+	}
+
+	if hasOffSize {
+		item.offSize = src[2]
+
+		n += 1
+
+	}
+	{
+		arrayLength := int(item.offsetsCount())
+		width := int(item.offSize)
+		if arrayLength != 0 && (width < 1 || width > 4) {
+			return item, 0, fmt.Errorf("reading CFFIndex: "+"invalid byte width %d", width)
+		}
+		if L := len(src); L < n+arrayLength*width {
+			return item, 0, fmt.Errorf("reading CFFIndex: "+"EOF: expected length: %d, got %d", n+arrayLength*width, L)
+		}
+
+		item.offsets = make([]uint32, arrayLength) // allocation guarded by the previous check
+		for i := range item.offsets {
+			switch width {
+			case 1:
+				item.offsets[i] = uint32(src[n+i*width])
+			case 2:
+				item.offsets[i] = uint32(binary.BigEndian.Uint16(src[n+i*width:]))
+			case 3:
+				item.offsets[i] = uint32(src[n+i*width+0])<<16 | uint32(src[n+i*width+1])<<8 | uint32(src[n+i*width+2])
+			case 4:
+				item.offsets[i] = binary.BigEndian.Uint32(src[n+i*width:])
+			}
+		}
+		n += arrayLength * width
+	}
+	if err := item.afterOffsets(); err != nil {
+		return item, 0, fmt.Errorf("reading CFFIndex: %w", err)
+	}
+	{
+		arrayLength := int(item.dataLength())
+
+		L := int(n + arrayLength)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading CFFIndex: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		item.data = src[n:L]
+		n = L
+	}
+	return item, n, nil
+}
+
+func ParseDeviceTable(src []byte) (DeviceTable, int, error) {
+	var item DeviceTable
+
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading DeviceTable: "+"EOF: expected length: 6, got %d", L)
+	}
+	format := uint16(binary.BigEndian.Uint16(src[4:]))
+	var (
+		read int
+		err  error
+	)
+	switch format {
+	case 1:
+		item, read, err = ParseDeviceTableHinting(src[0:])
+	case 32768:
+		item, read, err = ParseDeviceTableVariation(src[0:])
+	default:
+		err = fmt.Errorf("unsupported DeviceTable format %d", format)
+	}
+	if err != nil {
+		return item, 0, fmt.Errorf("reading DeviceTable: %w", err)
+	}
+
+	return item, read, nil
+}
+
+func ParseDeviceTableHinting(src []byte) (DeviceTableHinting, int, error) {
+	var item DeviceTableHinting
+	n := 0
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading DeviceTableHinting: "+"EOF: expected length: 6, got %d", L)
+	}
+	_ = src[5] // early bound checking
+	item.startSize = binary.BigEndian.Uint16(src[0:])
+	item.endSize = binary.BigEndian.Uint16(src[2:])
+	item.deltaFormat = binary.BigEndian.Uint16(src[4:])
+
+	n += 6
+
+	{
+		arrayLength := int(item.endSize)
+
+		if L := len(src); L < 6+arrayLength*2 {
+			return item, 0, fmt.Errorf("reading DeviceTableHinting: "+"EOF: expected length: %d, got %d", 6+arrayLength*2, L)
+		}
+
+		item.deltaValues = make([]uint16, arrayLength) // allocation guarded by the previous check
+		for i := range item.deltaValues {
+			item.deltaValues[i] = binary.BigEndian.Uint16(src[6+i*2:])
+		}
+		n += arrayLength * 2
+	}
+	return item, n, nil
+}
+
+func ParseDeviceTableVariation(src []byte) (DeviceTableVariation, int, error) {
+	var item DeviceTableVariation
+	n := 0
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading DeviceTableVariation: "+"EOF: expected length: 6, got %d", L)
+	}
+	item.mustParse(src)
+	n += 6
+	return item, n, nil
+}
+
 func ParseElement(src []byte, parentSrc []byte) (Element, int, error) {
 	var item Element
 	n := 0
@@ -47,6 +204,7 @@ func ParseElement(src []byte, parentSrc []byte) (Element, int, error) {
 	item.A = int32(binary.BigEndian.Uint32(src[0:]))
 	offsetV := int(binary.BigEndian.Uint32(src[4:]))
 	arrayLengthVarSizes := int(binary.BigEndian.Uint16(src[8:]))
+
 	n += 10
 
 	{
@@ -59,7 +217,7 @@ func ParseElement(src []byte, parentSrc []byte) (Element, int, error) {
 			var err error
 			item.v, _, err = parseVarSize(parentSrc[offsetV:])
 			if err != nil {
-				return item, 0, fmt.Errorf("reading Element: %s", err)
+				return item, 0, fmt.Errorf("reading Element: %w", err)
 			}
 
 		}
@@ -85,8 +243,9 @@ func ParseElement(src []byte, parentSrc []byte) (Element, int, error) {
 			var err error
 			item.VarSizes[i], _, err = parseVarSize(parentSrc[offset:])
 			if err != nil {
-				return item, 0, fmt.Errorf("reading Element: %s", err)
+				return item, 0, fmt.Errorf("reading Element: %w", err)
 			}
+
 		}
 		n += arrayLengthVarSizes * 4
 	}
@@ -94,20 +253,25 @@ func ParseElement(src []byte, parentSrc []byte) (Element, int, error) {
 		return item, 0, fmt.Errorf("reading Element: "+"EOF: expected length: n + 4, got %d", L)
 	}
 	arrayLengthSl := int(binary.BigEndian.Uint32(src[n:]))
+
 	n += 4
 
 	{
 
-		offset := n
-		for i := 0; i < arrayLengthSl; i++ {
-			elem, read, err := ParseSubElement(src[offset:], parentSrc)
+		elementSize := sizeSubElement()
+		if L := len(src); L < n+arrayLengthSl*elementSize {
+			return item, 0, fmt.Errorf("reading Element: "+"EOF: expected length: %d, got %d", n+arrayLengthSl*elementSize, L)
+		}
+
+		item.sl = make([]SubElement, arrayLengthSl) // allocation guarded by the previous check
+		for i := range item.sl {
+			var err error
+			item.sl[i], _, err = ParseSubElement(src[n+i*elementSize:], parentSrc)
 			if err != nil {
-				return item, 0, fmt.Errorf("reading Element: %s", err)
+				return item, 0, fmt.Errorf("reading Element: %w", err)
 			}
-			item.sl = append(item.sl, elem)
-			offset += read
 		}
-		n = offset
+		n += arrayLengthSl * elementSize
 	}
 	return item, n, nil
 }
@@ -134,7 +298,7 @@ func ParseImplicitITF(src []byte) (ImplicitITF, int, error) {
 		err = fmt.Errorf("unsupported ImplicitITF format %d", format)
 	}
 	if err != nil {
-		return item, 0, fmt.Errorf("reading ImplicitITF: %s", err)
+		return item, 0, fmt.Errorf("reading ImplicitITF: %w", err)
 	}
 
 	return item, read, nil
@@ -183,6 +347,7 @@ func ParsePassArg(src []byte) (PassArg, int, error) {
 	item.kind = binary.BigEndian.Uint16(src[0:])
 	item.version = binary.BigEndian.Uint16(src[2:])
 	item.count = int32(binary.BigEndian.Uint32(src[4:]))
+
 	n += 8
 
 	{
@@ -192,7 +357,7 @@ func ParsePassArg(src []byte) (PassArg, int, error) {
 		)
 		item.customWithArg, read, err = parseWithArgument(src[8:], int(item.count), uint16(item.kind), uint16(item.version))
 		if err != nil {
-			return item, 0, fmt.Errorf("reading PassArg: %s", err)
+			return item, 0, fmt.Errorf("reading PassArg: %w", err)
 		}
 		n += read
 	}
@@ -208,6 +373,7 @@ func ParseRootTable(src []byte) (RootTable, int, error) {
 	_ = src[3] // early bound checking
 	offsetE := int(binary.BigEndian.Uint16(src[0:]))
 	arrayLengthEs := int(binary.BigEndian.Uint16(src[2:]))
+
 	n += 4
 
 	{
@@ -220,7 +386,7 @@ func ParseRootTable(src []byte) (RootTable, int, error) {
 			var err error
 			item.E, _, err = ParseElement(src[offsetE:], src)
 			if err != nil {
-				return item, 0, fmt.Errorf("reading RootTable: %s", err)
+				return item, 0, fmt.Errorf("reading RootTable: %w", err)
 			}
 
 		}
@@ -231,7 +397,7 @@ func ParseRootTable(src []byte) (RootTable, int, error) {
 		for i := 0; i < arrayLengthEs; i++ {
 			elem, read, err := ParseElement(src[offset:], src)
 			if err != nil {
-				return item, 0, fmt.Errorf("reading RootTable: %s", err)
+				return item, 0, fmt.Errorf("reading RootTable: %w", err)
 			}
 			item.Es = append(item.Es, elem)
 			offset += read
@@ -248,6 +414,7 @@ func ParseSubElement(src []byte, grandParentSrc []byte) (SubElement, int, error)
 		return item, 0, fmt.Errorf("reading SubElement: "+"EOF: expected length: 2, got %d", L)
 	}
 	offsetV := int(binary.BigEndian.Uint16(src[0:]))
+
 	n += 2
 
 	{
@@ -260,7 +427,7 @@ func ParseSubElement(src []byte, grandParentSrc []byte) (SubElement, int, error)
 			var err error
 			item.v, _, err = parseVarSize(grandParentSrc[offsetV:])
 			if err != nil {
-				return item, 0, fmt.Errorf("reading SubElement: %s", err)
+				return item, 0, fmt.Errorf("reading SubElement: %w", err)
 			}
 
 		}
@@ -278,7 +445,7 @@ func ParseVariableThenFixed(src []byte) (VariableThenFixed, int, error) {
 		)
 		item.v, read, err = parseVarSize(src[0:])
 		if err != nil {
-			return item, 0, fmt.Errorf("reading VariableThenFixed: %s", err)
+			return item, 0, fmt.Errorf("reading VariableThenFixed: %w", err)
 		}
 		n += read
 	}
@@ -293,11 +460,23 @@ func ParseVariableThenFixed(src []byte) (VariableThenFixed, int, error) {
 	item.c[2] = src[n+8]
 	item.c[3] = src[n+9]
 	item.c[4] = src[n+10]
+
 	n += 11
 
 	return item, n, nil
 }
 
+func ParseWithAdjacentBitfields(src []byte) (WithAdjacentBitfields, int, error) {
+	var item WithAdjacentBitfields
+	n := 0
+	if L := len(src); L < 5 {
+		return item, 0, fmt.Errorf("reading WithAdjacentBitfields: "+"EOF: expected length: 5, got %d", L)
+	}
+	item.mustParse(src)
+	n += 5
+	return item, n, nil
+}
+
 func ParseWithArray(src []byte) (WithArray, int, error) {
 	var item WithArray
 	n := 0
@@ -309,323 +488,3119 @@ func ParseWithArray(src []byte) (WithArray, int, error) {
 	return item, n, nil
 }
 
-func ParseWithChildArgument(src []byte, arrayCount int, kind uint16, version uint16) (WithChildArgument, int, error) {
-	var item WithChildArgument
+func ParseWithBinarySize(src []byte) (WithBinarySize, int, error) {
+	var item WithBinarySize
 	n := 0
+	if L := len(src); L < 8 {
+		return item, 0, fmt.Errorf("reading WithBinarySize: "+"EOF: expected length: 8, got %d", L)
+	}
+	_ = src[7] // early bound checking
+	item.count = int(binary.BigEndian.Uint16(src[0:]))
+	item.delta = int32(int16(binary.BigEndian.Uint16(src[2:])))
+	item.offset = int(int32((uint32(src[4])<<16|uint32(src[5])<<8|uint32(src[6]))<<8) >> 8)
+	item.small = int16(int8(src[7]))
+
+	n += 8
+
 	{
-		var (
-			err  error
-			read int
-		)
-		item.child, read, err = parseWithArgument(src[0:], arrayCount, kind, version)
-		if err != nil {
-			return item, 0, fmt.Errorf("reading WithChildArgument: %s", err)
+		arrayLength := int(item.count)
+
+		if L := len(src); L < 8+arrayLength*2 {
+			return item, 0, fmt.Errorf("reading WithBinarySize: "+"EOF: expected length: %d, got %d", 8+arrayLength*2, L)
 		}
-		n += read
+
+		item.deltas = make([]int, arrayLength) // allocation guarded by the previous check
+		for i := range item.deltas {
+			item.deltas[i] = int(int16(binary.BigEndian.Uint16(src[8+i*2:])))
+		}
+		n += arrayLength * 2
+	}
+	if L := len(src); L < n+10 {
+		return item, 0, fmt.Errorf("reading WithBinarySize: "+"EOF: expected length: n + 10, got %d", L)
+	}
+	_ = src[n+9] // early bound checking
+	item.size = uintptr(binary.BigEndian.Uint32(src[n:]))
+	item.values[0] = uint64(uint32(src[n+4])<<16 | uint32(src[n+5])<<8 | uint32(src[n+6]))
+	item.values[1] = uint64(uint32(src[n+7])<<16 | uint32(src[n+8])<<8 | uint32(src[n+9]))
+
+	n += 10
+
+	return item, n, nil
+}
+
+func ParseWithBitStream(src []byte) (WithBitStream, int, error) {
+	var item WithBitStream
+	n := 0
+	if L := len(src); L < 3 {
+		return item, 0, fmt.Errorf("reading WithBitStream: "+"EOF: expected length: 3, got %d", L)
 	}
+	_ = src[2] // early bound checking
+	item.entryFormat = src[0]
+	item.mapCount = binary.BigEndian.Uint16(src[1:])
+
+	n += 3
+
 	{
-		var (
-			err  error
-			read int
-		)
-		item.child2, read, err = parseWithArgument(src[n:], arrayCount, kind, version)
-		if err != nil {
-			return item, 0, fmt.Errorf("reading WithChildArgument: %s", err)
+		arrayLength := int(item.mapCount)
+		bitWidth := 8 * (((int(item.entryFormat) >> 4) & 3) + 1)
+		if bitWidth < 1 || bitWidth > 32 {
+			return item, 0, fmt.Errorf("reading WithBitStream: "+"invalid bit width %d", bitWidth)
 		}
-		n += read
+		byteLength := (arrayLength*bitWidth + 7) / 8
+		if L := len(src); L < 3+byteLength {
+			return item, 0, fmt.Errorf("reading WithBitStream: "+"EOF: expected length: %d, got %d", 3+byteLength, L)
+		}
+		item.entries = make([]uint32, arrayLength) // allocation guarded by the previous check
+		for i := range item.entries {
+			var v uint32
+			for b := i * bitWidth; b < (i+1)*bitWidth; b++ {
+				v = v<<1 | uint32(src[3+b/8]>>(7-b%8))&1
+			}
+			item.entries[i] = v
+		}
+		n += byteLength
+	}
+	if L := len(src); L < n+1 {
+		return item, 0, fmt.Errorf("reading WithBitStream: "+"EOF: expected length: n + 1, got %d", L)
+	}
+	item.pointCount = src[n]
+
+	n += 1
+
+	{
+		arrayLength := int(item.pointCount)
+		bitWidth := int(item.entryFormat)&0xF + 1
+		if bitWidth < 1 || bitWidth > 16 {
+			return item, 0, fmt.Errorf("reading WithBitStream: "+"invalid bit width %d", bitWidth)
+		}
+		byteLength := (arrayLength*bitWidth + 7) / 8
+		if L := len(src); L < n+byteLength {
+			return item, 0, fmt.Errorf("reading WithBitStream: "+"EOF: expected length: %d, got %d", n+byteLength, L)
+		}
+		item.points = make([]uint16, arrayLength) // allocation guarded by the previous check
+		for i := range item.points {
+			var v uint16
+			for b := i * bitWidth; b < (i+1)*bitWidth; b++ {
+				v = v<<1 | uint16(src[n+b/8]>>(7-b%8))&1
+			}
+			item.points[i] = v
+		}
+		n += byteLength
 	}
 	return item, n, nil
 }
 
-func ParseWithImplicitITF(src []byte) (WithImplicitITF, int, error) {
-	var item WithImplicitITF
+func ParseWithBitfields(src []byte) (WithBitfields, int, error) {
+	var item WithBitfields
 	n := 0
-	if L := len(src); L < 4 {
-		return item, 0, fmt.Errorf("reading WithImplicitITF: "+"EOF: expected length: 4, got %d", L)
+	if L := len(src); L < 7 {
+		return item, 0, fmt.Errorf("reading WithBitfields: "+"EOF: expected length: 7, got %d", L)
 	}
-	item.field1 = binary.BigEndian.Uint32(src[0:])
-	n += 4
+	_ = src[6] // early bound checking
+	item.lookupType = binary.BigEndian.Uint16(src[0:])
+	{
+		bits := binary.BigEndian.Uint16(src[2:])
+		item.lookupFlag = uint16(bits & 0xff)
+		item.markAttachType = uint8((bits >> 8) & 0xff)
+	}
+	item.subtableCount = binary.BigEndian.Uint16(src[4:])
+	{
+		bits := src[6]
+		item.rightToLeft = bits&0x1 != 0
+		item.innerBitCount = uint8((bits >> 1) & 0xf)
+		item.entrySize = uint8((bits >> 5) & 0x3)
+	}
+
+	n += 7
 
 	{
-		var (
-			err  error
-			read int
-		)
-		item.itf, read, err = ParseImplicitITF(src[4:])
-		if err != nil {
-			return item, 0, fmt.Errorf("reading WithImplicitITF: %s", err)
+		arrayLength := int(item.subtableCount)
+
+		if L := len(src); L < 7+arrayLength*2 {
+			return item, 0, fmt.Errorf("reading WithBitfields: "+"EOF: expected length: %d, got %d", 7+arrayLength*2, L)
 		}
-		n += read
+
+		item.subtableOffsets = make([]uint16, arrayLength) // allocation guarded by the previous check
+		for i := range item.subtableOffsets {
+			item.subtableOffsets[i] = binary.BigEndian.Uint16(src[7+i*2:])
+		}
+		n += arrayLength * 2
 	}
 	return item, n, nil
 }
 
-func ParseWithOffset(src []byte, offsetToSliceCount int) (WithOffset, int, error) {
-	var item WithOffset
+func ParseWithBools(src []byte) (WithBools, int, error) {
+	var item WithBools
 	n := 0
-	if L := len(src); L < 19 {
-		return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: 19, got %d", L)
+	if L := len(src); L < 5 {
+		return item, 0, fmt.Errorf("reading WithBools: "+"EOF: expected length: 5, got %d", L)
 	}
-	_ = src[18] // early bound checking
-	item.version = binary.BigEndian.Uint16(src[0:])
-	offsetOffsetToSlice := int(binary.BigEndian.Uint32(src[2:]))
-	offsetOffsetToStruct := int(binary.BigEndian.Uint32(src[6:]))
-	item.a = src[10]
-	item.b = src[11]
-	item.c = src[12]
-	offsetOffsetToUnbounded := int(binary.BigEndian.Uint16(src[13:]))
-	offsetOptional := int(binary.BigEndian.Uint32(src[15:]))
-	n += 19
+	_ = src[4] // early bound checking
+	item.a = src[0] != 0
+	item.b = src[1] != 0
+	item.c[0] = src[2] != 0
+	item.c[1] = src[3] != 0
+	arrayLengthD := int(src[4])
 
-	{
+	n += 5
 
-		if offsetOffsetToSlice != 0 { // ignore null offset
-			if L := len(src); L < offsetOffsetToSlice {
-				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToSlice, L)
-			}
+	{
 
-			if L := len(src); L < offsetOffsetToSlice+offsetToSliceCount*8 {
-				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToSlice+offsetToSliceCount*8, L)
-			}
+		if L := len(src); L < 5+arrayLengthD {
+			return item, 0, fmt.Errorf("reading WithBools: "+"EOF: expected length: %d, got %d", 5+arrayLengthD, L)
+		}
 
-			item.offsetToSlice = make([]uint64, offsetToSliceCount) // allocation guarded by the previous check
-			for i := range item.offsetToSlice {
-				item.offsetToSlice[i] = binary.BigEndian.Uint64(src[offsetOffsetToSlice+i*8:])
-			}
-			offsetOffsetToSlice += offsetToSliceCount * 8
+		item.d = make([]bool, arrayLengthD) // allocation guarded by the previous check
+		for i := range item.d {
+			item.d[i] = src[5+i] != 0
 		}
+		n += arrayLengthD * 1
 	}
-	{
+	return item, n, nil
+}
 
-		if offsetOffsetToStruct != 0 { // ignore null offset
-			if L := len(src); L < offsetOffsetToStruct {
-				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToStruct, L)
-			}
+func ParseWithByteWidth(src []byte) (WithByteWidth, int, error) {
+	var item WithByteWidth
+	n := 0
+	if L := len(src); L < 3 {
+		return item, 0, fmt.Errorf("reading WithByteWidth: "+"EOF: expected length: 3, got %d", L)
+	}
+	_ = src[2] // early bound checking
+	item.count = binary.BigEndian.Uint16(src[0:])
+	item.offSize = src[2]
 
-			var err error
-			item.offsetToStruct, _, err = parseVarSize(src[offsetOffsetToStruct:])
-			if err != nil {
-				return item, 0, fmt.Errorf("reading WithOffset: %s", err)
-			}
+	n += 3
 
+	{
+		arrayLength := int(item.count)
+		width := int(item.offSize)
+		if arrayLength != 0 && (width < 1 || width > 4) {
+			return item, 0, fmt.Errorf("reading WithByteWidth: "+"invalid byte width %d", width)
+		}
+		if L := len(src); L < 3+arrayLength*width {
+			return item, 0, fmt.Errorf("reading WithByteWidth: "+"EOF: expected length: %d, got %d", 3+arrayLength*width, L)
+		}
+
+		item.offsets = make([]uint32, arrayLength) // allocation guarded by the previous check
+		for i := range item.offsets {
+			switch width {
+			case 1:
+				item.offsets[i] = uint32(src[3+i*width])
+			case 2:
+				item.offsets[i] = uint32(binary.BigEndian.Uint16(src[3+i*width:]))
+			case 3:
+				item.offsets[i] = uint32(src[3+i*width+0])<<16 | uint32(src[3+i*width+1])<<8 | uint32(src[3+i*width+2])
+			case 4:
+				item.offsets[i] = binary.BigEndian.Uint32(src[3+i*width:])
+			}
 		}
+		n += arrayLength * width
+	}
+	if L := len(src); L < n+3 {
+		return item, 0, fmt.Errorf("reading WithByteWidth: "+"EOF: expected length: n + 3, got %d", L)
 	}
+	_ = src[n+2] // early bound checking
+	item.format = src[n]
+	arrayLengthSmall := int(binary.BigEndian.Uint16(src[n+1:]))
+
+	n += 3
+
 	{
 
-		if offsetOffsetToUnbounded != 0 { // ignore null offset
-			if L := len(src); L < offsetOffsetToUnbounded {
-				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToUnbounded, L)
-			}
+		width := int(item.format&1 + 1)
+		if arrayLengthSmall != 0 && (width < 1 || width > 2) {
+			return item, 0, fmt.Errorf("reading WithByteWidth: "+"invalid byte width %d", width)
+		}
+		if L := len(src); L < n+arrayLengthSmall*width {
+			return item, 0, fmt.Errorf("reading WithByteWidth: "+"EOF: expected length: %d, got %d", n+arrayLengthSmall*width, L)
+		}
 
-			item.offsetToUnbounded = src[offsetOffsetToUnbounded:]
+		item.small = make([]uint16, arrayLengthSmall) // allocation guarded by the previous check
+		for i := range item.small {
+			switch width {
+			case 1:
+				item.small[i] = uint16(src[n+i*width])
+			case 2:
+				item.small[i] = binary.BigEndian.Uint16(src[n+i*width:])
+			}
 		}
+		n += arrayLengthSmall * width
 	}
 	{
+		arrayLength := int(item.count)
+		width := int(item.offSize)
+		if arrayLength != 0 && (width < 1 || width > 4) {
+			return item, 0, fmt.Errorf("reading WithByteWidth: "+"invalid byte width %d", width)
+		}
 
-		if offsetOptional != 0 { // ignore null offset
-			if L := len(src); L < offsetOptional {
-				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOptional, L)
+		if L := len(src); L < n+arrayLength*width {
+			return item, 0, fmt.Errorf("reading WithByteWidth: "+"EOF: expected length: %d, got %d", n+arrayLength*width, L)
+		}
+
+		item.tables = make([]WithArray, arrayLength) // allocation guarded by the previous check
+		for i := range item.tables {
+			var offset int
+			switch width {
+			case 1:
+				offset = int(src[n+i*width])
+			case 2:
+				offset = int(binary.BigEndian.Uint16(src[n+i*width:]))
+			case 3:
+				offset = int(uint32(src[n+i*width+0])<<16 | uint32(src[n+i*width+1])<<8 | uint32(src[n+i*width+2]))
+			case 4:
+				offset = int(binary.BigEndian.Uint32(src[n+i*width:]))
+			}
+			// ignore null offsets
+			if offset == 0 {
+				continue
+			}
+
+			if L := len(src); L < offset {
+				return item, 0, fmt.Errorf("reading WithByteWidth: "+"EOF: expected length: %d, got %d", offset, L)
 			}
 
-			var tmpOptional varSize
 			var err error
-			tmpOptional, _, err = parseVarSize(src[offsetOptional:])
+			item.tables[i], _, err = ParseWithArray(src[offset:])
 			if err != nil {
-				return item, 0, fmt.Errorf("reading WithOffset: %s", err)
+				return item, 0, fmt.Errorf("reading WithByteWidth: %w", err)
 			}
 
-			item.optional = &tmpOptional
 		}
+		n += arrayLength * width
 	}
 	return item, n, nil
 }
 
-func ParseWithOffsetArray(src []byte) (WithOffsetArray, int, error) {
-	var item WithOffsetArray
+func ParseWithChecksums(src []byte) (WithChecksums, int, error) {
+	var item WithChecksums
 	n := 0
-	if L := len(src); L < 2 {
-		return item, 0, fmt.Errorf("reading WithOffsetArray: "+"EOF: expected length: 2, got %d", L)
+	if L := len(src); L < 24 {
+		return item, 0, fmt.Errorf("reading WithChecksums: "+"EOF: expected length: 24, got %d", L)
+	}
+	item.mustParse(src)
+	n += 24
+	return item, n, nil
+}
+
+func ParseWithChildArgument(src []byte, arrayCount int, kind uint16, version uint16) (WithChildArgument, int, error) {
+	var item WithChildArgument
+	n := 0
+	{
+		var (
+			err  error
+			read int
+		)
+		item.child, read, err = parseWithArgument(src[0:], arrayCount, kind, version)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithChildArgument: %w", err)
+		}
+		n += read
 	}
-	arrayLengthArray := int(binary.BigEndian.Uint16(src[0:]))
-	n += 2
-
 	{
+		var (
+			err  error
+			read int
+		)
+		item.child2, read, err = parseWithArgument(src[n:], arrayCount, kind, version)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithChildArgument: %w", err)
+		}
+		n += read
+	}
+	return item, n, nil
+}
 
-		if L := len(src); L < 2+arrayLengthArray*4 {
-			return item, 0, fmt.Errorf("reading WithOffsetArray: "+"EOF: expected length: %d, got %d", 2+arrayLengthArray*4, L)
+func ParseWithCodecs(src []byte) (WithCodecs, int, error) {
+	var item WithCodecs
+	n := 0
+	if L := len(src); L < 18 {
+		return item, 0, fmt.Errorf("reading WithCodecs: "+"EOF: expected length: 18, got %d", L)
+	}
+	_ = src[17] // early bound checking
+	valueCreated, _, err := parseDate(src[0:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithCodecs: "+"invalid created: %w", err)
+	}
+	item.created = valueCreated
+	valueModified, _, err := parseDate(src[8:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithCodecs: "+"invalid modified: %w", err)
+	}
+	item.modified = valueModified
+	arrayLengthDates := int(binary.BigEndian.Uint16(src[16:]))
+
+	n += 18
+
+	{
+
+		if L := len(src); L < 18+arrayLengthDates*8 {
+			return item, 0, fmt.Errorf("reading WithCodecs: "+"EOF: expected length: %d, got %d", 18+arrayLengthDates*8, L)
 		}
 
-		item.array = make([]WithSlices, arrayLengthArray) // allocation guarded by the previous check
-		for i := range item.array {
-			offset := int(binary.BigEndian.Uint32(src[2+i*4:]))
-			// ignore null offsets
-			if offset == 0 {
-				continue
+		item.dates = make([]time.Time, arrayLengthDates) // allocation guarded by the previous check
+		for i := range item.dates {
+			valueDates, _, err := parseDate(src[18+i*8:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithCodecs: "+"invalid dates[%d]: %w", i, err)
 			}
+			item.dates[i] = valueDates
+		}
+		n += arrayLengthDates * 8
+	}
+	{
+		var (
+			err  error
+			read int
+		)
+		item.value, read, err = parseBigInt(src[n:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithCodecs: %w", err)
+		}
+		n += read
+	}
+	if L := len(src); L < n+1 {
+		return item, 0, fmt.Errorf("reading WithCodecs: "+"EOF: expected length: n + 1, got %d", L)
+	}
+	arrayLengthValues := int(src[n])
 
-			if L := len(src); L < offset {
-				return item, 0, fmt.Errorf("reading WithOffsetArray: "+"EOF: expected length: %d, got %d", offset, L)
+	n += 1
+
+	{
+
+		offset := n
+		for i := 0; i < arrayLengthValues; i++ {
+			elem, read, err := parseBigInt(src[offset:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithCodecs: %w", err)
+			}
+			item.values = append(item.values, elem)
+			offset += read
+		}
+		n = offset
+	}
+	if L := len(src); L < n+29 {
+		return item, 0, fmt.Errorf("reading WithCodecs: "+"EOF: expected length: n + 29, got %d", L)
+	}
+	_ = src[n+28] // early bound checking
+	offsetLargest := int(binary.BigEndian.Uint16(src[n:]))
+	valuePeriod0, _, err := parseDate(src[n+2:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithCodecs: "+"invalid period[0]: %w", err)
+	}
+	item.period[0] = valuePeriod0
+	valuePeriod1, _, err := parseDate(src[n+10:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithCodecs: "+"invalid period[1]: %w", err)
+	}
+	item.period[1] = valuePeriod1
+	valueRecord, _, err := parseDateRecord(src[n+18:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithCodecs: %w", err)
+	}
+	item.record = valueRecord
+	arrayLengthRecords := int(src[n+28])
+
+	n += 29
+
+	{
+
+		if offsetLargest != 0 { // ignore null offset
+			if L := len(src); L < offsetLargest {
+				return item, 0, fmt.Errorf("reading WithCodecs: "+"EOF: expected length: %d, got %d", offsetLargest, L)
 			}
 
 			var err error
-			item.array[i], _, err = ParseWithSlices(src[offset:])
+			item.largest, _, err = parseBigInt(src[offsetLargest:])
 			if err != nil {
-				return item, 0, fmt.Errorf("reading WithOffsetArray: %s", err)
+				return item, 0, fmt.Errorf("reading WithCodecs: %w", err)
 			}
+
 		}
-		n += arrayLengthArray * 4
+	}
+	{
+
+		if L := len(src); L < n+arrayLengthRecords*10 {
+			return item, 0, fmt.Errorf("reading WithCodecs: "+"EOF: expected length: %d, got %d", n+arrayLengthRecords*10, L)
+		}
+
+		item.records = make([]dateRecord, arrayLengthRecords) // allocation guarded by the previous check
+		for i := range item.records {
+			valueRecords, _, err := parseDateRecord(src[n+i*10:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithCodecs: %w", err)
+			}
+			item.records[i] = valueRecords
+		}
+		n += arrayLengthRecords * 10
 	}
 	return item, n, nil
 }
 
-func ParseWithOpaque(src []byte) (WithOpaque, int, error) {
-	var item WithOpaque
+func ParseWithCompression(src []byte) (WithCompression, int, error) {
+	var item WithCompression
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading WithCompression: "+"EOF: expected length: 4, got %d", L)
+	}
+	item.origLength = binary.BigEndian.Uint32(src[0:])
+
+	n += 4
+
+	{
+		inflated, read, err := inflateZlib(src[4:], int(item.origLength))
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithCompression: %w", err)
+		}
+		item.data = inflated
+		n += read
+	}
+	if L := len(src); L < n+10 {
+		return item, 0, fmt.Errorf("reading WithCompression: "+"EOF: expected length: n + 10, got %d", L)
+	}
+	_ = src[n+9] // early bound checking
+	item.docLength = binary.BigEndian.Uint32(src[n:])
+	offsetDoc := int(binary.BigEndian.Uint32(src[n+4:]))
+	item.arrayLen = binary.BigEndian.Uint16(src[n+8:])
+
+	n += 10
+
+	{
+
+		if offsetDoc != 0 { // ignore null offset
+			if L := len(src); L < offsetDoc {
+				return item, 0, fmt.Errorf("reading WithCompression: "+"EOF: expected length: %d, got %d", offsetDoc, L)
+			}
+
+			inflated, read, err := inflateGzip(src[offsetDoc:], int(item.docLength))
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithCompression: %w", err)
+			}
+			item.doc = inflated
+			_ = read
+		}
+	}
+	{
+		inflated, read, err := inflateDeflate(src[n:], int(item.arrayLen))
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithCompression: %w", err)
+		}
+		{
+			var err error
+			item.array, _, err = ParseWithArray(inflated[0:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithCompression: %w", err)
+			}
+
+		}
+		n += read
+	}
+	return item, n, nil
+}
+
+func ParseWithConstrainedRecords(src []byte, numGlyphs int) (WithConstrainedRecords, int, error) {
+	var item WithConstrainedRecords
 	n := 0
 	if L := len(src); L < 2 {
-		return item, 0, fmt.Errorf("reading WithOpaque: "+"EOF: expected length: 2, got %d", L)
+		return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"EOF: expected length: 2, got %d", L)
 	}
-	item.f = binary.BigEndian.Uint16(src[0:])
+	arrayLengthRecords := int(binary.BigEndian.Uint16(src[0:]))
+
 	n += 2
 
 	{
 
-		err := item.parseOpaque(src[:])
-		if err != nil {
-			return item, 0, fmt.Errorf("reading WithOpaque: %s", err)
+		if L := len(src); L < 2+arrayLengthRecords*4 {
+			return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"EOF: expected length: %d, got %d", 2+arrayLengthRecords*4, L)
+		}
+
+		item.records = make([]constrainedRecord, arrayLengthRecords) // allocation guarded by the previous check
+		for i := range item.records {
+			valueRecords, _, err := parseConstrainedRecord(src[2+i*4:], numGlyphs)
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"%w", nestedValidationError(err, fmt.Sprintf("WithConstrainedRecords.records[%d]", i)))
+			}
+			item.records[i] = valueRecords
 		}
+		n += arrayLengthRecords * 4
+	}
+	if L := len(src); L < n+14 {
+		return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"EOF: expected length: n + 14, got %d", L)
+	}
+	_ = src[n+13] // early bound checking
+	valueFirst, _, err := parseConstrainedRecord(src[n:], numGlyphs)
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"%w", nestedValidationError(err, "WithConstrainedRecords.first"))
 	}
+	item.first = valueFirst
+	valuePair0, _, err := parseConstrainedRecord(src[n+4:], numGlyphs)
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"%w", nestedValidationError(err, "WithConstrainedRecords.pair[0]"))
+	}
+	item.pair[0] = valuePair0
+	valuePair1, _, err := parseConstrainedRecord(src[n+8:], numGlyphs)
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"%w", nestedValidationError(err, "WithConstrainedRecords.pair[1]"))
+	}
+	item.pair[1] = valuePair1
+	offsetNested := int(binary.BigEndian.Uint16(src[n+12:]))
+
+	n += 14
+
 	{
 
-		read, err := item.parseOpaqueWithLength(src[:])
-		if err != nil {
-			return item, 0, fmt.Errorf("reading WithOpaque: %s", err)
+		if offsetNested != 0 { // ignore null offset
+			if L := len(src); L < offsetNested {
+				return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"EOF: expected length: %d, got %d", offsetNested, L)
+			}
+
+			var err error
+			item.nested, _, err = ParseWithConstraints(src[offsetNested:], numGlyphs)
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithConstrainedRecords: "+"%w", nestedValidationError(err, "WithConstrainedRecords.nested"))
+			}
+
 		}
-		n = read
 	}
 	return item, n, nil
 }
 
-func ParseWithRawdata(src []byte, defautCount int, startToCount int) (WithRawdata, int, error) {
-	var item WithRawdata
+func ParseWithConstraints(src []byte, numGlyphs int) (WithConstraints, int, error) {
+	var item WithConstraints
 	n := 0
-	if L := len(src); L < 4 {
-		return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: 4, got %d", L)
+	if L := len(src); L < 8 {
+		return item, 0, fmt.Errorf("reading WithConstraints: "+"EOF: expected length: 8, got %d", L)
 	}
-	item.length = binary.BigEndian.Uint32(src[0:])
-	n += 4
+	_ = src[7] // early bound checking
+	item.format = binary.BigEndian.Uint16(src[0:])
+	item.unitsPerEm = binary.BigEndian.Uint16(src[2:])
+	item.count = binary.BigEndian.Uint16(src[4:])
+	item.defaultGlyph = binary.BigEndian.Uint16(src[6:])
+
+	n += 8
 
 	{
+		arrayLength := int(item.count)
 
-		L := int(4 + defautCount)
-		if len(src) < L {
-			return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: %d, got %d", L, len(src))
+		if L := len(src); L < 8+arrayLength*2 {
+			return item, 0, fmt.Errorf("reading WithConstraints: "+"EOF: expected length: %d, got %d", 8+arrayLength*2, L)
 		}
-		item.defaut = src[4:L]
-		n = L
+
+		item.glyphs = make([]uint16, arrayLength) // allocation guarded by the previous check
+		for i := range item.glyphs {
+			item.glyphs[i] = binary.BigEndian.Uint16(src[8+i*2:])
+		}
+		n += arrayLength * 2
 	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithConstraints: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	item.classes[0] = int8(src[n])
+	item.classes[1] = int8(src[n+1])
+
+	n += 2
+
+	if err := item.validate(numGlyphs); err != nil {
+		return item, 0, fmt.Errorf("reading WithConstraints: %w", err)
+	}
+	return item, n, nil
+}
+
+func ParseWithConstructors(src []byte) (WithConstructors, int, error) {
+	var item WithConstructors
+	n := 0
+	if L := len(src); L < 9 {
+		return item, 0, fmt.Errorf("reading WithConstructors: "+"EOF: expected length: 9, got %d", L)
+	}
+	_ = src[8] // early bound checking
+	item.scale.fromUint(binary.BigEndian.Uint16(src[0:]))
+	valueClass, err := markClassFromUint(binary.BigEndian.Uint16(src[2:]))
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithConstructors: "+"invalid class: %w", err)
+	}
+	item.class = valueClass
+	if err := item.name.fromUint(binary.BigEndian.Uint16(src[4:])); err != nil {
+		return item, 0, fmt.Errorf("reading WithConstructors: "+"invalid name: %w", err)
+	}
+	item.readOnly = readOnlyFromUint(src[6])
+	arrayLengthClasses := int(binary.BigEndian.Uint16(src[7:]))
+
+	n += 9
+
 	{
 
-		L := int(0 + startToCount)
-		if len(src) < L {
-			return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: %d, got %d", L, len(src))
+		if L := len(src); L < 9+arrayLengthClasses*2 {
+			return item, 0, fmt.Errorf("reading WithConstructors: "+"EOF: expected length: %d, got %d", 9+arrayLengthClasses*2, L)
 		}
-		item.startTo = src[0:L]
-		n = L
+
+		item.classes = make([]markClass, arrayLengthClasses) // allocation guarded by the previous check
+		for i := range item.classes {
+			valueClasses, err := markClassFromUint(binary.BigEndian.Uint16(src[9+i*2:]))
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithConstructors: "+"invalid classes[%d]: %w", i, err)
+			}
+			item.classes[i] = valueClasses
+		}
+		n += arrayLengthClasses * 2
 	}
+	if L := len(src); L < n+9 {
+		return item, 0, fmt.Errorf("reading WithConstructors: "+"EOF: expected length: n + 9, got %d", L)
+	}
+	_ = src[n+8] // early bound checking
+	valuePair0, err := markClassFromUint(binary.BigEndian.Uint16(src[n:]))
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithConstructors: "+"invalid pair[0]: %w", err)
+	}
+	item.pair[0] = valuePair0
+	valuePair1, err := markClassFromUint(binary.BigEndian.Uint16(src[n+2:]))
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithConstructors: "+"invalid pair[1]: %w", err)
+	}
+	item.pair[1] = valuePair1
+	valueRecord, _, err := parseClassRecord(src[n+4:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithConstructors: %w", err)
+	}
+	item.record = valueRecord
+	arrayLengthRecords := int(src[n+8])
+
+	n += 9
+
 	{
 
-		item.currentToEnd = src[n:]
-		n = len(src)
+		if L := len(src); L < n+arrayLengthRecords*4 {
+			return item, 0, fmt.Errorf("reading WithConstructors: "+"EOF: expected length: %d, got %d", n+arrayLengthRecords*4, L)
+		}
+
+		item.records = make([]classRecord, arrayLengthRecords) // allocation guarded by the previous check
+		for i := range item.records {
+			valueRecords, _, err := parseClassRecord(src[n+i*4:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithConstructors: %w", err)
+			}
+			item.records[i] = valueRecords
+		}
+		n += arrayLengthRecords * 4
+	}
+	return item, n, nil
+}
+
+func ParseWithEnumElements(src []byte) (WithEnumElements, int, error) {
+	var item WithEnumElements
+	n := 0
+	if L := len(src); L < 5 {
+		return item, 0, fmt.Errorf("reading WithEnumElements: "+"EOF: expected length: 5, got %d", L)
+	}
+	_ = src[4] // early bound checking
+	item.formats[0] = paintFormat(src[0])
+	item.formats[1] = paintFormat(src[1])
+	item.flags[0] = src[2] != 0
+	item.flags[1] = src[3] != 0
+	arrayLengthModes := int(src[4])
+	for i := range item.formats {
+		switch item.formats[i] {
+		case paintSolid, paintLinear, paintRadial, paintGlyph:
+		default:
+			return item, 0, fmt.Errorf("reading WithEnumElements: %w", expectError{field: "WithEnumElements.formats", expected: "paintSolid or paintLinear or paintRadial or paintGlyph", got: uint8(item.formats[i])})
+		}
 	}
+	for i := range item.flags {
+		if b := src[2+i]; b > 1 {
+			return item, 0, fmt.Errorf("reading WithEnumElements: %w", expectError{field: "WithEnumElements.flags", expected: "0x0 or 0x1", got: b})
+		}
+	}
+	n += 5
+
 	{
 
-		item.startToEnd = src[0:]
-		n = len(src)
+		if L := len(src); L < 5+arrayLengthModes {
+			return item, 0, fmt.Errorf("reading WithEnumElements: "+"EOF: expected length: %d, got %d", 5+arrayLengthModes, L)
+		}
+
+		item.modes = make([]extendMode, arrayLengthModes) // allocation guarded by the previous check
+		for i := range item.modes {
+			item.modes[i] = extendMode(src[5+i])
+			switch item.modes[i] {
+			case extendPad, extendRepeat, extendReflect:
+			default:
+				return item, 0, fmt.Errorf("reading WithEnumElements: %w", expectError{field: "WithEnumElements.modes", expected: "extendPad or extendRepeat or extendReflect", got: uint8(item.modes[i])})
+			}
+		}
+		n += arrayLengthModes * 1
+	}
+	if L := len(src); L < n+1 {
+		return item, 0, fmt.Errorf("reading WithEnumElements: "+"EOF: expected length: n + 1, got %d", L)
 	}
+	arrayLengthStates := int(src[n])
+
+	n += 1
+
 	{
 
-		L := int(item.length)
-		if len(src) < L {
-			return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: %d, got %d", L, len(src))
+		if L := len(src); L < n+arrayLengthStates {
+			return item, 0, fmt.Errorf("reading WithEnumElements: "+"EOF: expected length: %d, got %d", n+arrayLengthStates, L)
+		}
+
+		item.states = make([]bool, arrayLengthStates) // allocation guarded by the previous check
+		for i := range item.states {
+			item.states[i] = src[n+i] != 0
+			if b := src[n+i]; b > 1 {
+				return item, 0, fmt.Errorf("reading WithEnumElements: %w", expectError{field: "WithEnumElements.states", expected: "0x0 or 0x1", got: b})
+			}
+		}
+		n += arrayLengthStates * 1
+	}
+	if L := len(src); L < n+6 {
+		return item, 0, fmt.Errorf("reading WithEnumElements: "+"EOF: expected length: n + 6, got %d", L)
+	}
+	_ = src[n+5] // early bound checking
+	valueRecord, _, err := parseEnumRecord(src[n:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithEnumElements: %w", err)
+	}
+	item.record = valueRecord
+	valuePair0, _, err := parseEnumRecord(src[n+2:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithEnumElements: %w", err)
+	}
+	item.pair[0] = valuePair0
+	valuePair1, _, err := parseEnumRecord(src[n+4:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithEnumElements: %w", err)
+	}
+	item.pair[1] = valuePair1
+
+	n += 6
+
+	return item, n, nil
+}
+
+func ParseWithEnums(src []byte) (WithEnums, int, error) {
+	var item WithEnums
+	n := 0
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading WithEnums: "+"EOF: expected length: 6, got %d", L)
+	}
+	_ = src[5] // early bound checking
+	item.format = paintFormat(src[0])
+	item.hidden = src[1] != 0
+	item.shown = visible(src[2] != 0)
+	item.extend = extendMode(src[3])
+	item.version = subtableFlagVersion(binary.BigEndian.Uint16(src[4:]))
+	switch item.format {
+	case paintSolid, paintLinear, paintRadial, paintGlyph:
+	default:
+		return item, 0, fmt.Errorf("reading WithEnums: %w", expectError{field: "WithEnums.format", expected: "paintSolid or paintLinear or paintRadial or paintGlyph", got: uint8(item.format)})
+	}
+	if b := src[1]; b > 1 {
+		return item, 0, fmt.Errorf("reading WithEnums: %w", expectError{field: "WithEnums.hidden", expected: "0x0 or 0x1", got: b})
+	}
+	if b := src[2]; b > 1 {
+		return item, 0, fmt.Errorf("reading WithEnums: %w", expectError{field: "WithEnums.shown", expected: "0x0 or 0x1", got: b})
+	}
+	switch item.extend {
+	case extendPad, extendRepeat, extendReflect:
+	default:
+		return item, 0, fmt.Errorf("reading WithEnums: %w", expectError{field: "WithEnums.extend", expected: "extendPad or extendRepeat or extendReflect", got: uint8(item.extend)})
+	}
+	switch item.version {
+	case subtableFlagVersion1, subtableFlagVersion2:
+	default:
+		return item, 0, fmt.Errorf("reading WithEnums: %w", expectError{field: "WithEnums.version", expected: "subtableFlagVersion1 or subtableFlagVersion2", got: uint16(item.version)})
+	}
+	n += 6
+
+	return item, n, nil
+}
+
+func ParseWithExpect(src []byte) (WithExpect, int, error) {
+	var item WithExpect
+	n := 0
+	if L := len(src); L < 10 {
+		return item, 0, fmt.Errorf("reading WithExpect: "+"EOF: expected length: 10, got %d", L)
+	}
+	_ = src[9] // early bound checking
+	item.majorVersion = binary.BigEndian.Uint16(src[0:])
+	item.minorVersion = binary.BigEndian.Uint16(src[2:])
+	item.magicNumber = binary.BigEndian.Uint32(src[4:])
+	arrayLengthGlyphs := int(binary.BigEndian.Uint16(src[8:]))
+	if item.majorVersion != 1 {
+		return item, 0, fmt.Errorf("reading WithExpect: %w", expectError{field: "WithExpect.majorVersion", expected: "0x1", got: item.majorVersion})
+	}
+	if item.minorVersion != 0 && item.minorVersion != 1 {
+		return item, 0, fmt.Errorf("reading WithExpect: %w", expectError{field: "WithExpect.minorVersion", expected: "0x0 or 0x1", got: item.minorVersion})
+	}
+	if item.magicNumber != 1594834165 {
+		return item, 0, fmt.Errorf("reading WithExpect: %w", expectError{field: "WithExpect.magicNumber", expected: "0x5f0f3cf5", got: item.magicNumber})
+	}
+	n += 10
+
+	{
+
+		if L := len(src); L < 10+arrayLengthGlyphs*2 {
+			return item, 0, fmt.Errorf("reading WithExpect: "+"EOF: expected length: %d, got %d", 10+arrayLengthGlyphs*2, L)
+		}
+
+		item.glyphs = make([]uint16, arrayLengthGlyphs) // allocation guarded by the previous check
+		for i := range item.glyphs {
+			item.glyphs[i] = binary.BigEndian.Uint16(src[10+i*2:])
+		}
+		n += arrayLengthGlyphs * 2
+	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithExpect: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	_ = src[n+1] // early bound checking
+	item.offSize = int8(src[n])
+	arrayLengthRecords := int(src[n+1])
+	if item.offSize != -1 && item.offSize != 1 && item.offSize != 2 {
+		return item, 0, fmt.Errorf("reading WithExpect: %w", expectError{field: "WithExpect.offSize", expected: "-0x1 or 0x1 or 0x2", got: item.offSize})
+	}
+	n += 2
+
+	{
+
+		if L := len(src); L < n+arrayLengthRecords*4 {
+			return item, 0, fmt.Errorf("reading WithExpect: "+"EOF: expected length: %d, got %d", n+arrayLengthRecords*4, L)
+		}
+
+		item.records = make([]WithFixedExpect, arrayLengthRecords) // allocation guarded by the previous check
+		for i := range item.records {
+			valueRecords, _, err := ParseWithFixedExpect(src[n+i*4:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithExpect: %w", err)
+			}
+			item.records[i] = valueRecords
+		}
+		n += arrayLengthRecords * 4
+	}
+	return item, n, nil
+}
+
+func ParseWithExtension(src []byte) (WithExtension, int, error) {
+	var item WithExtension
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithExtension: "+"EOF: expected length: 2, got %d", L)
+	}
+	item.kind = lookupVersion(binary.BigEndian.Uint16(src[0:]))
+
+	n += 2
+
+	{
+		var (
+			read int
+			err  error
+		)
+		switch item.kind {
+		case lookupVersion1:
+			item.subtable, read, err = parseLookupSubtable1(src[2:])
+		case lookupVersion2:
+			item.subtable, read, err = parseLookupSubtable2(src[2:])
+		case lookupVersionExtension:
+			var wrapper lookupSubtableExtension
+			wrapper, read, err = parseLookupSubtableExtension(src[2:])
+			item.subtable = wrapper.inner
+			item.isExtension = true
+		default:
+			err = fmt.Errorf("unsupported lookupSubtableVersion %d", item.kind)
+		}
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithExtension: %w", err)
+		}
+		n += read
+	}
+	return item, n, nil
+}
+
+func ParseWithFixedExpect(src []byte) (WithFixedExpect, int, error) {
+	var item WithFixedExpect
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading WithFixedExpect: "+"EOF: expected length: 4, got %d", L)
+	}
+	_ = src[3] // early bound checking
+	item.version = binary.BigEndian.Uint16(src[0:])
+	item.value = int16(binary.BigEndian.Uint16(src[2:]))
+	if item.version != 2 {
+		return item, 0, fmt.Errorf("reading WithFixedExpect: %w", expectError{field: "WithFixedExpect.version", expected: "0x2", got: item.version})
+	}
+	n += 4
+
+	return item, n, nil
+}
+
+func ParseWithFixedReserved(src []byte) (WithFixedReserved, int, error) {
+	var item WithFixedReserved
+	n := 0
+	if L := len(src); L < 10 {
+		return item, 0, fmt.Errorf("reading WithFixedReserved: "+"EOF: expected length: 10, got %d", L)
+	}
+	item.mustParse(src)
+	n += 10
+	return item, n, nil
+}
+
+func ParseWithFixedSkipped(src []byte) (WithFixedSkipped, int, error) {
+	var item WithFixedSkipped
+	n := 0
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading WithFixedSkipped: "+"EOF: expected length: 6, got %d", L)
+	}
+	item.mustParse(src)
+	n += 6
+	return item, n, nil
+}
+
+func ParseWithFloats(src []byte) (WithFloats, int, error) {
+	var item WithFloats
+	n := 0
+	if L := len(src); L < 32 {
+		return item, 0, fmt.Errorf("reading WithFloats: "+"EOF: expected length: 32, got %d", L)
+	}
+	_ = src[31] // early bound checking
+	item.single = math.Float32frombits(binary.BigEndian.Uint32(src[0:]))
+	item.double = math.Float64frombits(binary.BigEndian.Uint64(src[4:]))
+	item.named = angle(math.Float32frombits(binary.BigEndian.Uint32(src[12:])))
+	item.scale = float32(int16(binary.BigEndian.Uint16(src[16:]))) / (1 << 14)
+	item.advance = float64(int32(binary.BigEndian.Uint32(src[18:]))) / (1 << 16)
+	item.matrix[0] = float32(int16(binary.BigEndian.Uint16(src[22:]))) / (1 << 14)
+	item.matrix[1] = float32(int16(binary.BigEndian.Uint16(src[24:]))) / (1 << 14)
+	item.matrix[2] = float32(int16(binary.BigEndian.Uint16(src[26:]))) / (1 << 14)
+	item.matrix[3] = float32(int16(binary.BigEndian.Uint16(src[28:]))) / (1 << 14)
+	arrayLengthDeltas := int(binary.BigEndian.Uint16(src[30:]))
+
+	n += 32
+
+	{
+
+		if L := len(src); L < 32+arrayLengthDeltas*4 {
+			return item, 0, fmt.Errorf("reading WithFloats: "+"EOF: expected length: %d, got %d", 32+arrayLengthDeltas*4, L)
+		}
+
+		item.deltas = make([]angle, arrayLengthDeltas) // allocation guarded by the previous check
+		for i := range item.deltas {
+			item.deltas[i] = angle(int32(binary.BigEndian.Uint32(src[32+i*4:]))) / (1 << 16)
+		}
+		n += arrayLengthDeltas * 4
+	}
+	return item, n, nil
+}
+
+func ParseWithHookedArray(src []byte) (WithHookedArray, int, error) {
+	var item WithHookedArray
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading WithHookedArray: "+"EOF: expected length: 4, got %d", L)
+	}
+	valuePair0, _, err := parseHookedElement(src[0:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithHookedArray: %w", err)
+	}
+	item.pair[0] = valuePair0
+	valuePair1, _, err := parseHookedElement(src[2:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithHookedArray: %w", err)
+	}
+	item.pair[1] = valuePair1
+
+	n += 4
+
+	return item, n, nil
+}
+
+func ParseWithHooks(src []byte, numGlyphs int) (WithHooks, int, error) {
+	var item WithHooks
+	n := 0
+	item.parseStart(src)
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithHooks: "+"EOF: expected length: 2, got %d", L)
+	}
+	item.count = binary.BigEndian.Uint16(src[0:])
+
+	n += 2
+
+	if err := item.afterCount(numGlyphs); err != nil {
+		return item, 0, fmt.Errorf("reading WithHooks: %w", err)
+	}
+	{
+		arrayLength := int(item.count)
+
+		if L := len(src); L < 2+arrayLength*2 {
+			return item, 0, fmt.Errorf("reading WithHooks: "+"EOF: expected length: %d, got %d", 2+arrayLength*2, L)
+		}
+
+		item.glyphs = make([]uint16, arrayLength) // allocation guarded by the previous check
+		for i := range item.glyphs {
+			item.glyphs[i] = binary.BigEndian.Uint16(src[2+i*2:])
+		}
+		n += arrayLength * 2
+	}
+	if L := len(src); L < n+1 {
+		return item, 0, fmt.Errorf("reading WithHooks: "+"EOF: expected length: n + 1, got %d", L)
+	}
+	item.flags = src[n]
+
+	n += 1
+
+	hasExtra := item.flags&1 != 0
+	{
+		expectedLength := n
+		if hasExtra {
+			expectedLength += 2
+		}
+		if L := len(src); L < expectedLength {
+			return item, 0, fmt.Errorf("reading WithHooks: "+"EOF: expected length: %d, got %d", expectedLength, L)
+		}
+	}
+
+	if hasExtra {
+		item.extra = binary.BigEndian.Uint16(src[n:])
+
+		n += 2
+
+	}
+	if hasExtra {
+		item.afterExtra()
+	}
+	{
+		arrayLength := int(item.half)
+
+		if L := len(src); L < n+arrayLength*2 {
+			return item, 0, fmt.Errorf("reading WithHooks: "+"EOF: expected length: %d, got %d", n+arrayLength*2, L)
+		}
+
+		item.pairs = make([]uint16, arrayLength) // allocation guarded by the previous check
+		for i := range item.pairs {
+			item.pairs[i] = binary.BigEndian.Uint16(src[n+i*2:])
+		}
+		n += arrayLength * 2
+	}
+	if L := len(src); L < n+4 {
+		return item, 0, fmt.Errorf("reading WithHooks: "+"EOF: expected length: n + 4, got %d", L)
+	}
+	valueFixed, _, err := parseWithFixedHooks(src[n:], numGlyphs)
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithHooks: %w", err)
+	}
+	item.fixed = valueFixed
+
+	n += 4
+
+	if err := item.validate(); err != nil {
+		return item, 0, fmt.Errorf("reading WithHooks: %w", err)
+	}
+	return item, n, nil
+}
+
+func ParseWithImplicitITF(src []byte) (WithImplicitITF, int, error) {
+	var item WithImplicitITF
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading WithImplicitITF: "+"EOF: expected length: 4, got %d", L)
+	}
+	item.field1 = binary.BigEndian.Uint32(src[0:])
+
+	n += 4
+
+	{
+		var (
+			err  error
+			read int
+		)
+		item.itf, read, err = ParseImplicitITF(src[4:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithImplicitITF: %w", err)
+		}
+		n += read
+	}
+	return item, n, nil
+}
+
+func ParseWithImplicitITFAtOffset(src []byte) (WithImplicitITFAtOffset, int, error) {
+	var item WithImplicitITFAtOffset
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithImplicitITFAtOffset: "+"EOF: expected length: 2, got %d", L)
+	}
+	offsetDevice := int(binary.BigEndian.Uint16(src[0:]))
+
+	n += 2
+
+	{
+
+		if offsetDevice != 0 { // ignore null offset
+			if L := len(src); L < offsetDevice {
+				return item, 0, fmt.Errorf("reading WithImplicitITFAtOffset: "+"EOF: expected length: %d, got %d", offsetDevice, L)
+			}
+
+			var (
+				err  error
+				read int
+			)
+			item.device, read, err = ParseDeviceTable(src[offsetDevice:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithImplicitITFAtOffset: %w", err)
+			}
+			offsetDevice += read
+		}
+	}
+	return item, n, nil
+}
+
+func ParseWithMaps(src []byte) (WithMaps, int, error) {
+	var item WithMaps
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: 2, got %d", L)
+	}
+	arrayLengthTables := int(binary.BigEndian.Uint16(src[0:]))
+
+	n += 2
+
+	{
+		var records []tableRecord
+
+		if L := len(src); L < 2+arrayLengthTables*16 {
+			return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: %d, got %d", 2+arrayLengthTables*16, L)
+		}
+
+		records = make([]tableRecord, arrayLengthTables) // allocation guarded by the previous check
+		for i := range records {
+			records[i].mustParse(src[2+i*16:])
+		}
+		n += arrayLengthTables * 16
+		item.tables = make(map[Tag]tableRecord, len(records))
+		for _, record := range records {
+			if _, isDuplicate := item.tables[record.tag]; isDuplicate {
+				return item, 0, fmt.Errorf("reading WithMaps: "+"duplicate key %v in tables", record.tag)
+			}
+			item.tables[record.tag] = record
+		}
+	}
+	if L := len(src); L < n+4 {
+		return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: n + 4, got %d", L)
+	}
+	_ = src[n+3] // early bound checking
+	item.version = binary.BigEndian.Uint16(src[n:])
+	arrayLengthScripts := int(binary.BigEndian.Uint16(src[n+2:]))
+
+	n += 4
+
+	{
+		var records []scriptRecord
+
+		elementSize := sizeScriptRecord()
+		if L := len(src); L < n+arrayLengthScripts*elementSize {
+			return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: %d, got %d", n+arrayLengthScripts*elementSize, L)
+		}
+
+		records = make([]scriptRecord, arrayLengthScripts) // allocation guarded by the previous check
+		for i := range records {
+			var err error
+			records[i], _, err = parseScriptRecord(src[n+i*elementSize:], src)
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithMaps: %w", err)
+			}
+		}
+		n += arrayLengthScripts * elementSize
+		item.scripts = make(map[Tag]scriptRecord, len(records))
+		for _, record := range records {
+			if _, isDuplicate := item.scripts[record.tag]; isDuplicate {
+				return item, 0, fmt.Errorf("reading WithMaps: "+"duplicate key %v in scripts", record.tag)
+			}
+			item.scripts[record.tag] = record
+		}
+	}
+	if L := len(src); L < n+1 {
+		return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: n + 1, got %d", L)
+	}
+	arrayLengthAxes := int(src[n])
+
+	n += 1
+
+	{
+		var records []axisRecord
+
+		if L := len(src); L < n+arrayLengthAxes*8 {
+			return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: %d, got %d", n+arrayLengthAxes*8, L)
+		}
+
+		records = make([]axisRecord, arrayLengthAxes) // allocation guarded by the previous check
+		for i := range records {
+			valueRecords, _, err := parseAxisRecord(src[n+i*8:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithMaps: %w", err)
+			}
+			records[i] = valueRecords
+		}
+		n += arrayLengthAxes * 8
+		item.axes = make(map[opentype.Tag]axisRecord, len(records))
+		for _, record := range records {
+			if _, isDuplicate := item.axes[record.tag]; isDuplicate {
+				return item, 0, fmt.Errorf("reading WithMaps: "+"duplicate key %v in axes", record.tag)
+			}
+			item.axes[record.tag] = record
+		}
+	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	arrayLengthClasses := int(binary.BigEndian.Uint16(src[n:]))
+
+	n += 2
+
+	{
+		var records []classDef
+
+		if L := len(src); L < n+arrayLengthClasses*2 {
+			return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: %d, got %d", n+arrayLengthClasses*2, L)
+		}
+
+		records = make([]classDef, 0, arrayLengthClasses) // allocation guarded by the previous check
+		for i := 0; i < arrayLengthClasses; i++ {
+			offset := int(binary.BigEndian.Uint16(src[n+i*2:]))
+			// ignore null offsets
+			if offset == 0 {
+				continue
+			}
+
+			if L := len(src); L < offset {
+				return item, 0, fmt.Errorf("reading WithMaps: "+"EOF: expected length: %d, got %d", offset, L)
+			}
+
+			record, _, err := parseClassDef(src[offset:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithMaps: %w", err)
+			}
+			records = append(records, record)
+		}
+		n += arrayLengthClasses * 2
+		item.classes = make(map[uint16]classDef, len(records))
+		for _, record := range records {
+			if _, isDuplicate := item.classes[record.format]; isDuplicate {
+				return item, 0, fmt.Errorf("reading WithMaps: "+"duplicate key %v in classes", record.format)
+			}
+			item.classes[record.format] = record
+		}
+	}
+	return item, n, nil
+}
+
+func ParseWithOffset(src []byte, offsetToSliceCount int) (WithOffset, int, error) {
+	var item WithOffset
+	n := 0
+	if L := len(src); L < 19 {
+		return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: 19, got %d", L)
+	}
+	_ = src[18] // early bound checking
+	item.version = binary.BigEndian.Uint16(src[0:])
+	offsetOffsetToSlice := int(binary.BigEndian.Uint32(src[2:]))
+	offsetOffsetToStruct := int(binary.BigEndian.Uint32(src[6:]))
+	item.a = src[10]
+	item.b = src[11]
+	item.c = src[12]
+	offsetOffsetToUnbounded := int(binary.BigEndian.Uint16(src[13:]))
+	offsetOptional := int(binary.BigEndian.Uint32(src[15:]))
+
+	n += 19
+
+	{
+
+		if offsetOffsetToSlice != 0 { // ignore null offset
+			if L := len(src); L < offsetOffsetToSlice {
+				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToSlice, L)
+			}
+
+			if L := len(src); L < offsetOffsetToSlice+offsetToSliceCount*8 {
+				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToSlice+offsetToSliceCount*8, L)
+			}
+
+			item.offsetToSlice = make([]uint64, offsetToSliceCount) // allocation guarded by the previous check
+			for i := range item.offsetToSlice {
+				item.offsetToSlice[i] = binary.BigEndian.Uint64(src[offsetOffsetToSlice+i*8:])
+			}
+			offsetOffsetToSlice += offsetToSliceCount * 8
+		}
+	}
+	{
+
+		if offsetOffsetToStruct != 0 { // ignore null offset
+			if L := len(src); L < offsetOffsetToStruct {
+				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToStruct, L)
+			}
+
+			var err error
+			item.offsetToStruct, _, err = parseVarSize(src[offsetOffsetToStruct:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithOffset: %w", err)
+			}
+
+		}
+	}
+	{
+
+		if offsetOffsetToUnbounded != 0 { // ignore null offset
+			if L := len(src); L < offsetOffsetToUnbounded {
+				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOffsetToUnbounded, L)
+			}
+
+			item.offsetToUnbounded = src[offsetOffsetToUnbounded:]
+		}
+	}
+	{
+
+		if offsetOptional != 0 { // ignore null offset
+			if L := len(src); L < offsetOptional {
+				return item, 0, fmt.Errorf("reading WithOffset: "+"EOF: expected length: %d, got %d", offsetOptional, L)
+			}
+
+			var tmpOptional varSize
+			var err error
+			tmpOptional, _, err = parseVarSize(src[offsetOptional:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithOffset: %w", err)
+			}
+
+			item.optional = &tmpOptional
+		}
+	}
+	return item, n, nil
+}
+
+func ParseWithOffsetArray(src []byte) (WithOffsetArray, int, error) {
+	var item WithOffsetArray
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithOffsetArray: "+"EOF: expected length: 2, got %d", L)
+	}
+	arrayLengthArray := int(binary.BigEndian.Uint16(src[0:]))
+
+	n += 2
+
+	{
+
+		if L := len(src); L < 2+arrayLengthArray*4 {
+			return item, 0, fmt.Errorf("reading WithOffsetArray: "+"EOF: expected length: %d, got %d", 2+arrayLengthArray*4, L)
+		}
+
+		item.array = make([]WithSlices, arrayLengthArray) // allocation guarded by the previous check
+		for i := range item.array {
+			offset := int(binary.BigEndian.Uint32(src[2+i*4:]))
+			// ignore null offsets
+			if offset == 0 {
+				continue
+			}
+
+			if L := len(src); L < offset {
+				return item, 0, fmt.Errorf("reading WithOffsetArray: "+"EOF: expected length: %d, got %d", offset, L)
+			}
+
+			var err error
+			item.array[i], _, err = ParseWithSlices(src[offset:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithOffsetArray: %w", err)
+			}
+
+		}
+		n += arrayLengthArray * 4
+	}
+	return item, n, nil
+}
+
+func ParseWithOpaque(src []byte) (WithOpaque, int, error) {
+	var item WithOpaque
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithOpaque: "+"EOF: expected length: 2, got %d", L)
+	}
+	item.f = binary.BigEndian.Uint16(src[0:])
+
+	n += 2
+
+	{
+
+		err := item.parseOpaque(src[:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithOpaque: %w", err)
+		}
+	}
+	{
+
+		read, err := item.parseOpaqueWithLength(src[:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithOpaque: %w", err)
+		}
+		n = read
+	}
+	return item, n, nil
+}
+
+func ParseWithOptionalFields(src []byte) (WithOptionalFields, int, error) {
+	var item WithOptionalFields
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading WithOptionalFields: "+"EOF: expected length: 4, got %d", L)
+	}
+	_ = src[3] // early bound checking
+	item.valueFormat = binary.BigEndian.Uint16(src[0:])
+	item.count = binary.BigEndian.Uint16(src[2:])
+
+	n += 4
+
+	{
+		arrayLength := int(item.count)
+		elementSize := sizeValueRecord(uint16(item.valueFormat))
+		if L := len(src); L < 4+arrayLength*elementSize {
+			return item, 0, fmt.Errorf("reading WithOptionalFields: "+"EOF: expected length: %d, got %d", 4+arrayLength*elementSize, L)
+		}
+
+		item.records = make([]valueRecord, arrayLength) // allocation guarded by the previous check
+		for i := range item.records {
+			var err error
+			item.records[i], _, err = parseValueRecord(src[4+i*elementSize:], src, uint16(item.valueFormat))
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithOptionalFields: %w", err)
+			}
+		}
+		n += arrayLength * elementSize
+	}
+	{
+		var (
+			err  error
+			read int
+		)
+		item.single, read, err = parseValueRecord(src[n:], src, uint16(item.valueFormat))
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithOptionalFields: %w", err)
+		}
+		n += read
+	}
+	if L := len(src); L < n+1 {
+		return item, 0, fmt.Errorf("reading WithOptionalFields: "+"EOF: expected length: n + 1, got %d", L)
+	}
+	item.flags = src[n]
+
+	n += 1
+
+	hasExtra := item.flags&1 != 0
+	{
+		expectedLength := n + 2
+		if hasExtra {
+			expectedLength += 4
+		}
+		if L := len(src); L < expectedLength {
+			return item, 0, fmt.Errorf("reading WithOptionalFields: "+"EOF: expected length: %d, got %d", expectedLength, L)
+		}
+	}
+
+	if hasExtra {
+		item.extra = binary.BigEndian.Uint32(src[n:])
+
+		n += 4
+
+	}
+	item.last = binary.BigEndian.Uint16(src[n:])
+
+	n += 2
+
+	return item, n, nil
+}
+
+func ParseWithRawdata(src []byte, defautCount int, startToCount int) (WithRawdata, int, error) {
+	var item WithRawdata
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: 4, got %d", L)
+	}
+	item.length = binary.BigEndian.Uint32(src[0:])
+
+	n += 4
+
+	{
+
+		L := int(4 + defautCount)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		item.defaut = src[4:L]
+		n = L
+	}
+	{
+
+		L := int(0 + startToCount)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		item.startTo = src[0:L]
+		n = L
+	}
+	{
+
+		item.currentToEnd = src[n:]
+		n = len(src)
+	}
+	{
+
+		item.startToEnd = src[0:]
+		n = len(src)
+	}
+	{
+
+		L := int(item.length)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithRawdata: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		item.currentToOffset = src[n:L]
+		n = L
+	}
+	return item, n, nil
+}
+
+func ParseWithReserved(src []byte) (WithReserved, int, error) {
+	var item WithReserved
+	n := 0
+	if L := len(src); L < 12 {
+		return item, 0, fmt.Errorf("reading WithReserved: "+"EOF: expected length: 12, got %d", L)
+	}
+	_ = src[11] // early bound checking
+	item.version = binary.BigEndian.Uint16(src[0:])
+
+	item.flags = src[4]
+
+	item.glyphCount = binary.BigEndian.Uint16(src[8:])
+
+	for i, b := range src[2:4] {
+		if b != 0 {
+			return item, 0, fmt.Errorf("reading WithReserved: "+"non zero reserved byte at %d", 2+i)
+		}
+	}
+	for i, b := range src[5:8] {
+		if b != 0 {
+			return item, 0, fmt.Errorf("reading WithReserved: "+"non zero reserved byte at %d", 5+i)
+		}
+	}
+	for i, b := range src[10:12] {
+		if b != 0 {
+			return item, 0, fmt.Errorf("reading WithReserved: "+"non zero reserved byte at %d", 10+i)
+		}
+	}
+	n += 12
+
+	{
+		arrayLength := int(item.glyphCount)
+
+		L := int(12 + arrayLength)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithReserved: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		item.glyphs = src[12:L]
+		n = L
+	}
+	{
+		padding := (4 - (n)%4) % 4
+		if L := len(src); L < n+padding {
+			return item, 0, fmt.Errorf("reading WithReserved: "+"EOF: expected length: %d, got %d", n+padding, L)
+		}
+
+		for i, b := range src[n : n+padding] {
+			if b != 0 {
+				return item, 0, fmt.Errorf("reading WithReserved: "+"non zero reserved byte at %d", n+i)
+			}
+		}
+		n += padding
+	}
+	if L := len(src); L < n+8 {
+		return item, 0, fmt.Errorf("reading WithReserved: "+"EOF: expected length: n + 8, got %d", L)
+	}
+	_ = src[n+7] // early bound checking
+	item.tag = binary.BigEndian.Uint32(src[n:])
+
+	item.last = src[n+7]
+	for i, b := range src[n+4 : n+7] {
+		if b != 0 {
+			return item, 0, fmt.Errorf("reading WithReserved: "+"non zero reserved byte at %d", n+4+i)
+		}
+	}
+	n += 8
+
+	return item, n, nil
+}
+
+func ParseWithScalars(src []byte) (WithScalars, int, error) {
+	var item WithScalars
+	n := 0
+	if L := len(src); L < 24 {
+		return item, 0, fmt.Errorf("reading WithScalars: "+"EOF: expected length: 24, got %d", L)
+	}
+	_ = src[23] // early bound checking
+	item.version = opentype.Version16Dot16(binary.BigEndian.Uint32(src[0:]))
+	item.created = opentype.LongDateTime(binary.BigEndian.Uint64(src[4:]))
+	item.tag = opentype.Tag(binary.BigEndian.Uint32(src[12:]))
+	item.scale = opentype.F2Dot14(binary.BigEndian.Uint16(src[16:]))
+	item.advance = opentype.Fixed(binary.BigEndian.Uint32(src[18:]))
+	arrayLengthGlyphs := int(binary.BigEndian.Uint16(src[22:]))
+	if err := item.created.Validate(); err != nil {
+		return item, 0, fmt.Errorf("reading WithScalars: "+"invalid created: %w", err)
+	}
+	if err := item.tag.Validate(); err != nil {
+		return item, 0, fmt.Errorf("reading WithScalars: "+"invalid tag: %w", err)
+	}
+	n += 24
+
+	{
+
+		if L := len(src); L < 24+arrayLengthGlyphs*2 {
+			return item, 0, fmt.Errorf("reading WithScalars: "+"EOF: expected length: %d, got %d", 24+arrayLengthGlyphs*2, L)
+		}
+
+		item.glyphs = make([]opentype.GlyphID, arrayLengthGlyphs) // allocation guarded by the previous check
+		for i := range item.glyphs {
+			item.glyphs[i] = opentype.GlyphID(binary.BigEndian.Uint16(src[24+i*2:]))
+		}
+		n += arrayLengthGlyphs * 2
+	}
+	if L := len(src); L < n+5 {
+		return item, 0, fmt.Errorf("reading WithScalars: "+"EOF: expected length: n + 5, got %d", L)
+	}
+	_ = src[n+4] // early bound checking
+	item.paint = opentype.Offset24(uint32(src[n])<<16 | uint32(src[n+1])<<8 | uint32(src[n+2]))
+	item.subtable = opentype.Offset16(binary.BigEndian.Uint16(src[n+3:]))
+
+	n += 5
+
+	return item, n, nil
+}
+
+func ParseWithSkipped(src []byte) (WithSkipped, int, error) {
+	var item WithSkipped
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithSkipped: "+"EOF: expected length: 2, got %d", L)
+	}
+	arrayLengthGlyphs := int(binary.BigEndian.Uint16(src[0:]))
+
+	n += 2
+
+	{
+
+		if L := len(src); L < 2+arrayLengthGlyphs*2 {
+			return item, 0, fmt.Errorf("reading WithSkipped: "+"EOF: expected length: %d, got %d", 2+arrayLengthGlyphs*2, L)
+		}
+
+		item.glyphs = make([]uint16, arrayLengthGlyphs) // allocation guarded by the previous check
+		for i := range item.glyphs {
+			item.glyphs[i] = binary.BigEndian.Uint16(src[2+i*2:])
+		}
+		n += arrayLengthGlyphs * 2
+	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithSkipped: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	item.version = binary.BigEndian.Uint16(src[n:])
+
+	n += 2
+
+	return item, n, nil
+}
+
+func ParseWithSlices(src []byte) (WithSlices, int, error) {
+	var item WithSlices
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithSlices: "+"EOF: expected length: 2, got %d", L)
+	}
+	item.length = binary.BigEndian.Uint16(src[0:])
+
+	n += 2
+
+	{
+		arrayLength := int(item.length)
+
+		offset := 2
+		for i := 0; i < arrayLength; i++ {
+			elem, read, err := parseVarSize(src[offset:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithSlices: %w", err)
+			}
+			item.s1 = append(item.s1, elem)
+			offset += read
+		}
+		n = offset
+	}
+	return item, n, nil
+}
+
+func ParseWithSortedRecords(src []byte) (WithSortedRecords, int, error) {
+	var item WithSortedRecords
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading WithSortedRecords: "+"EOF: expected length: 2, got %d", L)
+	}
+	arrayLengthGlyphs := int(binary.BigEndian.Uint16(src[0:]))
+
+	n += 2
+
+	{
+
+		if L := len(src); L < 2+arrayLengthGlyphs*4 {
+			return item, 0, fmt.Errorf("reading WithSortedRecords: "+"EOF: expected length: %d, got %d", 2+arrayLengthGlyphs*4, L)
+		}
+
+		item.glyphs = make([]glyphRecord, arrayLengthGlyphs) // allocation guarded by the previous check
+		for i := range item.glyphs {
+			item.glyphs[i].mustParse(src[2+i*4:])
+		}
+		n += arrayLengthGlyphs * 4
+		for i := 1; i < len(item.glyphs); i++ {
+			if item.glyphs[i-1].glyph >= item.glyphs[i].glyph {
+				return item, 0, fmt.Errorf("reading WithSortedRecords: "+"glyphs not sorted at index %d", i)
+			}
+		}
+	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithSortedRecords: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	arrayLengthRanges := int(binary.BigEndian.Uint16(src[n:]))
+
+	n += 2
+
+	{
+
+		if L := len(src); L < n+arrayLengthRanges*6 {
+			return item, 0, fmt.Errorf("reading WithSortedRecords: "+"EOF: expected length: %d, got %d", n+arrayLengthRanges*6, L)
+		}
+
+		item.ranges = make([]rangeRecord, arrayLengthRanges) // allocation guarded by the previous check
+		for i := range item.ranges {
+			item.ranges[i].mustParse(src[n+i*6:])
+		}
+		n += arrayLengthRanges * 6
+	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithSortedRecords: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	arrayLengthKinds := int(binary.BigEndian.Uint16(src[n:]))
+
+	n += 2
+
+	{
+
+		if L := len(src); L < n+arrayLengthKinds*3 {
+			return item, 0, fmt.Errorf("reading WithSortedRecords: "+"EOF: expected length: %d, got %d", n+arrayLengthKinds*3, L)
+		}
+
+		item.kinds = make([]kindRecord, arrayLengthKinds) // allocation guarded by the previous check
+		for i := range item.kinds {
+			valueKinds, _, err := parseKindRecord(src[n+i*3:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithSortedRecords: %w", err)
+			}
+			item.kinds[i] = valueKinds
+		}
+		n += arrayLengthKinds * 3
+		for i := 1; i < len(item.kinds); i++ {
+			if item.kinds[i-1].start >= item.kinds[i].start {
+				return item, 0, fmt.Errorf("reading WithSortedRecords: "+"kinds not sorted at index %d", i)
+			}
+		}
+	}
+	return item, n, nil
+}
+
+func ParseWithStrictElements(src []byte) (WithStrictElements, int, error) {
+	var item WithStrictElements
+	n := 0
+	if L := len(src); L < 13 {
+		return item, 0, fmt.Errorf("reading WithStrictElements: "+"EOF: expected length: 13, got %d", L)
+	}
+	_ = src[12] // early bound checking
+	valueSingle, _, err := parseStrictRecord(src[0:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithStrictElements: %w", err)
+	}
+	item.single = valueSingle
+	valuePair0, _, err := parseStrictRecord(src[4:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithStrictElements: %w", err)
+	}
+	item.pair[0] = valuePair0
+	valuePair1, _, err := parseStrictRecord(src[8:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading WithStrictElements: %w", err)
+	}
+	item.pair[1] = valuePair1
+	arrayLengthRecords := int(src[12])
+
+	n += 13
+
+	{
+
+		if L := len(src); L < 13+arrayLengthRecords*4 {
+			return item, 0, fmt.Errorf("reading WithStrictElements: "+"EOF: expected length: %d, got %d", 13+arrayLengthRecords*4, L)
+		}
+
+		item.records = make([]strictRecord, arrayLengthRecords) // allocation guarded by the previous check
+		for i := range item.records {
+			valueRecords, _, err := parseStrictRecord(src[13+i*4:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithStrictElements: %w", err)
+			}
+			item.records[i] = valueRecords
+		}
+		n += arrayLengthRecords * 4
+	}
+	return item, n, nil
+}
+
+func ParseWithStrings(src []byte) (WithStrings, int, error) {
+	var item WithStrings
+	n := 0
+	if L := len(src); L < 3 {
+		return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: 3, got %d", L)
+	}
+	_ = src[2] // early bound checking
+	item.version = binary.BigEndian.Uint16(src[0:])
+	arrayLengthPascal := int(src[2])
+
+	n += 3
+
+	{
+		var raw []byte
+
+		L := int(3 + arrayLengthPascal)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		raw = src[3:L]
+		n = L
+
+		s, err := decodeMacRoman(raw)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"invalid pascal: %w", err)
+		}
+		item.pascal = s
+	}
+	{
+		if L := len(src); L < n+8 {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: n + 8, got %d", L)
+		}
+		raw := trimZeroPadding(src[n:n+8], 1)
+		n += 8
+		s, err := decodeASCII(raw)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"invalid fixed: %w", err)
+		}
+		item.fixed = s
+	}
+	{
+		if L := len(src); L < n+16 {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: n + 16, got %d", L)
+		}
+		raw := trimZeroPadding(src[n:n+16], 2)
+		n += 16
+		s, err := decodeUTF16BE(raw)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"invalid wide: %w", err)
+		}
+		item.wide = s
+	}
+	if L := len(src); L < n+8 {
+		return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: n + 8, got %d", L)
+	}
+	_ = src[n+7] // early bound checking
+	item.length = binary.BigEndian.Uint16(src[n:])
+	item.offset = binary.BigEndian.Uint16(src[n+2:])
+	offsetNamed := int(binary.BigEndian.Uint16(src[n+4:]))
+	item.nameLength = binary.BigEndian.Uint16(src[n+6:])
+
+	n += 8
+
+	{
+
+		if offsetNamed != 0 { // ignore null offset
+			if L := len(src); L < offsetNamed {
+				return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: %d, got %d", offsetNamed, L)
+			}
+
+			var raw []byte
+			arrayLength := int(item.length)
+
+			L := int(offsetNamed + arrayLength)
+			if len(src) < L {
+				return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: %d, got %d", L, len(src))
+			}
+			raw = src[offsetNamed:L]
+
+			s, err := decodeUTF16BE(raw)
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithStrings: "+"invalid named: %w", err)
+			}
+			item.named = name(s)
+
+		}
+	}
+	{
+		var raw []byte
+		arrayLength := int(item.nameLength)
+
+		L := int(n + arrayLength)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		raw = src[n:L]
+		n = L
+
+		s, err := decodeUTF8(raw)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"invalid utf8: %w", err)
+		}
+		item.utf8 = s
+	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	arrayLengthCounted := int(binary.BigEndian.Uint16(src[n:]))
+
+	n += 2
+
+	{
+		var raw []byte
+
+		L := int(n + arrayLengthCounted)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		raw = src[n:L]
+		n = L
+
+		s, err := decodeASCII(raw)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithStrings: "+"invalid counted: %w", err)
+		}
+		item.counted = s
+	}
+	return item, n, nil
+}
+
+func ParseWithTableChecksums(src []byte) (WithTableChecksums, int, error) {
+	var item WithTableChecksums
+	n := 0
+	if L := len(src); L < 10 {
+		return item, 0, fmt.Errorf("reading WithTableChecksums: "+"EOF: expected length: 10, got %d", L)
+	}
+	_ = src[9] // early bound checking
+	item.version = binary.BigEndian.Uint16(src[0:])
+	item.sum = binary.BigEndian.Uint32(src[2:])
+	item.adler = binary.BigEndian.Uint32(src[6:])
+
+	n += 10
+
+	{
+
+		item.data = src[10:]
+		n = len(src)
+	}
+	return item, n, nil
+}
+
+func ParseWithUint24(src []byte) (WithUint24, int, error) {
+	var item WithUint24
+	n := 0
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: 6, got %d", L)
+	}
+	_ = src[5] // early bound checking
+	item.varSelector = uint32(src[0])<<16 | uint32(src[1])<<8 | uint32(src[2])
+	arrayLengthGlyphs := int(uint32(src[3])<<16 | uint32(src[4])<<8 | uint32(src[5]))
+
+	n += 6
+
+	{
+
+		if L := len(src); L < 6+arrayLengthGlyphs*3 {
+			return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: %d, got %d", 6+arrayLengthGlyphs*3, L)
+		}
+
+		item.glyphs = make([]uint32, arrayLengthGlyphs) // allocation guarded by the previous check
+		for i := range item.glyphs {
+			item.glyphs[i] = uint32(src[6+i*3])<<16 | uint32(src[6+i*3+1])<<8 | uint32(src[6+i*3+2])
+		}
+		n += arrayLengthGlyphs * 3
+	}
+	if L := len(src); L < n+3 {
+		return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: n + 3, got %d", L)
+	}
+	_ = src[n+2] // early bound checking
+	item.delta = int(binary.BigEndian.Uint16(src[n:]))
+	arrayLengthLayers := int(src[n+2])
+
+	n += 3
+
+	{
+
+		L := int(n + arrayLengthLayers)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		item.layers = src[n:L]
+		n = L
+	}
+	if L := len(src); L < n+5 {
+		return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: n + 5, got %d", L)
+	}
+	_ = src[n+4] // early bound checking
+	offsetPaint := int(uint32(src[n])<<16 | uint32(src[n+1])<<8 | uint32(src[n+2]))
+	arrayLengthClips := int(binary.BigEndian.Uint16(src[n+3:]))
+
+	n += 5
+
+	{
+
+		if offsetPaint != 0 { // ignore null offset
+			if L := len(src); L < offsetPaint {
+				return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: %d, got %d", offsetPaint, L)
+			}
+
+			var err error
+			item.paint, _, err = ParseWithArray(src[offsetPaint:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithUint24: %w", err)
+			}
+
+		}
+	}
+	{
+
+		if L := len(src); L < n+arrayLengthClips*3 {
+			return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: %d, got %d", n+arrayLengthClips*3, L)
+		}
+
+		item.clips = make([]WithArray, arrayLengthClips) // allocation guarded by the previous check
+		for i := range item.clips {
+			offset := int(uint32(src[n+i*3])<<16 | uint32(src[n+i*3+1])<<8 | uint32(src[n+i*3+2]))
+			// ignore null offsets
+			if offset == 0 {
+				continue
+			}
+
+			if L := len(src); L < offset {
+				return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: %d, got %d", offset, L)
+			}
+
+			var err error
+			item.clips[i], _, err = ParseWithArray(src[offset:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithUint24: %w", err)
+			}
+
+		}
+		n += arrayLengthClips * 3
+	}
+	if L := len(src); L < n+6 {
+		return item, 0, fmt.Errorf("reading WithUint24: "+"EOF: expected length: n + 6, got %d", L)
+	}
+	item.ranges[0] = uint32(src[n])<<16 | uint32(src[n+1])<<8 | uint32(src[n+2])
+	item.ranges[1] = uint32(src[n+3])<<16 | uint32(src[n+4])<<8 | uint32(src[n+5])
+
+	n += 6
+
+	return item, n, nil
+}
+
+func ParseWithUniformSize(src []byte) (WithUniformSize, int, error) {
+	var item WithUniformSize
+	n := 0
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading WithUniformSize: "+"EOF: expected length: 6, got %d", L)
+	}
+	_ = src[5] // early bound checking
+	item.valueFormat = binary.BigEndian.Uint16(src[0:])
+	item.width = src[2]
+	item.count = src[3]
+	arrayLengthRecords := int(binary.BigEndian.Uint16(src[4:]))
+
+	n += 6
+
+	{
+
+		elementSize := sizeUniformRecord(int(item.count), int(item.count), int(item.count), uint16(item.valueFormat), uint8(item.width))
+		if L := len(src); L < 6+arrayLengthRecords*elementSize {
+			return item, 0, fmt.Errorf("reading WithUniformSize: "+"EOF: expected length: %d, got %d", 6+arrayLengthRecords*elementSize, L)
+		}
+
+		item.records = make([]uniformRecord, arrayLengthRecords) // allocation guarded by the previous check
+		for i := range item.records {
+			var err error
+			item.records[i], _, err = parseUniformRecord(src[6+i*elementSize:], src, int(item.count), int(item.count), int(item.count), uint16(item.valueFormat), uint8(item.width))
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithUniformSize: %w", err)
+			}
+		}
+		n += arrayLengthRecords * elementSize
+	}
+	return item, n, nil
+}
+
+func ParseWithUnion(src []byte) (WithUnion, int, error) {
+	var item WithUnion
+	n := 0
+	if L := len(src); L < 3 {
+		return item, 0, fmt.Errorf("reading WithUnion: "+"EOF: expected length: 3, got %d", L)
+	}
+	_ = src[2] // early bound checking
+	item.version = subtableFlagVersion(binary.BigEndian.Uint16(src[0:]))
+	item.otherField = src[2]
+
+	n += 3
+
+	{
+		var (
+			read int
+			err  error
+		)
+		switch item.version {
+		case subtableFlagVersion1:
+			item.data, read, err = parseSubtableITF1(src[3:])
+		case subtableFlagVersion2:
+			item.data, read, err = parseSubtableITF2(src[3:])
+		default:
+			err = fmt.Errorf("unsupported subtableITFVersion %d", item.version)
+		}
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithUnion: %w", err)
+		}
+		n += read
+	}
+	return item, n, nil
+}
+
+func ParseWithVarInts(src []byte) (WithVarInts, int, error) {
+	var item WithVarInts
+	n := 0
+	if L := len(src); L < 1 {
+		return item, 0, fmt.Errorf("reading WithVarInts: "+"EOF: expected length: 1, got %d", L)
+	}
+	item.flags = src[0]
+
+	n += 1
+
+	{
+		v, read, err := readUIntBase128(src[1:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithVarInts: %w", err)
+		}
+		item.origLength = uint32(v)
+		n += read
+	}
+	{
+		v, read, err := readUIntBase128(src[n:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithVarInts: %w", err)
+		}
+		item.transformed = uint32(v)
+		n += read
+	}
+	if L := len(src); L < n+2 {
+		return item, 0, fmt.Errorf("reading WithVarInts: "+"EOF: expected length: n + 2, got %d", L)
+	}
+	item.numberOfPoints = binary.BigEndian.Uint16(src[n:])
+
+	n += 2
+
+	{
+		arrayLength := int(item.numberOfPoints)
+		if L := len(src); L < n+arrayLength {
+			return item, 0, fmt.Errorf("reading WithVarInts: "+"EOF: expected length: %d, got %d", n+arrayLength, L)
+		}
+
+		item.endPoints = make([]uint16, arrayLength) // allocation guarded by the previous check
+		offset := n
+		for i := range item.endPoints {
+			v, read, err := read255UInt16(src[offset:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading WithVarInts: %w", err)
+			}
+			item.endPoints[i] = uint16(v)
+			offset += read
+		}
+		n = offset
+	}
+	{
+		count, read, err := read255UInt16(src[n:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithVarInts: %w", err)
+		}
+		n += read
+		arrayLengthGlyphIDs := int(count)
+
+		if L := len(src); L < n+arrayLengthGlyphIDs*4 {
+			return item, 0, fmt.Errorf("reading WithVarInts: "+"EOF: expected length: %d, got %d", n+arrayLengthGlyphIDs*4, L)
+		}
+
+		item.glyphIDs = make([]uint32, arrayLengthGlyphIDs) // allocation guarded by the previous check
+		for i := range item.glyphIDs {
+			item.glyphIDs[i] = binary.BigEndian.Uint32(src[n+i*4:])
+		}
+		n += arrayLengthGlyphIDs * 4
+	}
+	{
+		count, read, err := readUIntBase128(src[n:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithVarInts: %w", err)
+		}
+		n += read
+		arrayLengthTables := int(count)
+
+		if L := len(src); L < n+arrayLengthTables*4 {
+			return item, 0, fmt.Errorf("reading WithVarInts: "+"EOF: expected length: %d, got %d", n+arrayLengthTables*4, L)
+		}
+
+		item.tables = make([]WithAlias, arrayLengthTables) // allocation guarded by the previous check
+		for i := range item.tables {
+			item.tables[i].mustParse(src[n+i*4:])
+		}
+		n += arrayLengthTables * 4
+	}
+	{
+		v, read, err := readCFFInteger(src[n:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithVarInts: %w", err)
+		}
+		item.charset = int32(v)
+		n += read
+	}
+	{
+		v, read, err := readCFFReal(src[n:])
+		if err != nil {
+			return item, 0, fmt.Errorf("reading WithVarInts: %w", err)
+		}
+		item.italicAngle = float64(v)
+		n += read
+	}
+	return item, n, nil
+}
+
+func (item *WithAdjacentBitfields) mustParse(src []byte) {
+	_ = src[4] // early bound checking
+	{
+		bits := binary.BigEndian.Uint16(src[0:])
+		item.deltaFormat = uint16(bits & 0x3)
+		item.innerLevel = uint16((bits >> 2) & 0x3fff)
+	}
+	{
+		bits := binary.BigEndian.Uint16(src[2:])
+		item.outerLevel = uint16(bits & 0xfff)
+		item.isLast = (bits>>15)&0x1 != 0
+	}
+	{
+		bits := src[4]
+		item.deltaShift = int8(bits<<4) >> 4
+		item.deltaScale = int16(int8(bits) >> 4)
+	}
+}
+
+func (item *WithAlias) mustParse(src []byte) {
+	item.f = fl32FromUint(binary.BigEndian.Uint32(src[0:]))
+}
+
+func (item *WithArray) mustParse(src []byte) {
+	_ = src[20] // early bound checking
+	item.a = binary.BigEndian.Uint16(src[0:])
+	item.b[0] = binary.BigEndian.Uint32(src[2:])
+	item.b[1] = binary.BigEndian.Uint32(src[6:])
+	item.b[2] = binary.BigEndian.Uint32(src[10:])
+	item.b[3] = binary.BigEndian.Uint32(src[14:])
+	item.c[0] = src[18]
+	item.c[1] = src[19]
+	item.c[2] = src[20]
+}
+
+// computeAdler returns the checksum of the data described by [adler],
+// using [src] to resolve the range.
+func (item *WithChecksums) computeAdler(src []byte) (uint32, error) {
+	start, length := int(item.offset), int(item.length/2)
+	if start < 0 || length < 0 || len(src) < start+length {
+		return 0, fmt.Errorf("invalid range for adler: [%d, %d+%d), for length %d", start, start, length, len(src))
+	}
+	return adler32.Checksum(src[start : start+length]), nil
+}
+
+// computeCheckSum returns the checksum of the data described by [checkSum],
+// using [src] to resolve the range.
+func (item *WithChecksums) computeCheckSum(src []byte) (uint32, error) {
+	start, length := int(item.offset), int(item.length)
+	if start < 0 || length < 0 || len(src) < start+length {
+		return 0, fmt.Errorf("invalid range for checkSum: [%d, %d+%d), for length %d", start, start, length, len(src))
+	}
+	return checksumOpenType(src[start : start+length]), nil
+}
+
+// computeCrc returns the checksum of the data described by [crc],
+// using [src] to resolve the range.
+func (item *WithChecksums) computeCrc(src []byte) (uint32, error) {
+	// the checksum field itself is considered to be zero
+	if len(src) < 20 {
+		return 0, fmt.Errorf("EOF: expected length: 20, got %d", len(src))
+	}
+	return checksumCRC32Zeroed(src, 16), nil
+}
+
+func (item *WithChecksums) mustParse(src []byte) {
+	_ = src[23] // early bound checking
+	item.tableTag = binary.BigEndian.Uint32(src[0:])
+	item.checkSum = binary.BigEndian.Uint32(src[4:])
+	item.offset = binary.BigEndian.Uint32(src[8:])
+	item.length = binary.BigEndian.Uint32(src[12:])
+	item.crc = binary.BigEndian.Uint32(src[16:])
+	item.adler = binary.BigEndian.Uint32(src[20:])
+}
+
+// verifyChecksums checks that the fields storing a checksum
+// match their data, using [src] to resolve the ranges.
+// It is not called by the parsing function.
+func (item *WithChecksums) verifyChecksums(src []byte) error {
+	if got, err := item.computeCheckSum(src); err != nil {
+		return err
+	} else if got != uint32(item.checkSum) {
+		return checksumError{field: "WithChecksums.checkSum", expected: uint32(item.checkSum), got: got}
+	}
+	if got, err := item.computeCrc(src); err != nil {
+		return err
+	} else if got != uint32(item.crc) {
+		return checksumError{field: "WithChecksums.crc", expected: uint32(item.crc), got: got}
+	}
+	if got, err := item.computeAdler(src); err != nil {
+		return err
+	} else if got != uint32(item.adler) {
+		return checksumError{field: "WithChecksums.adler", expected: uint32(item.adler), got: got}
+	}
+	return nil
+}
+
+// FindRecords returns the index of the record in [records] whose count is [key],
+// using a binary search.
+func (item *WithCodecs) FindRecords(key uint16) (int, bool) {
+	records := item.records
+	low, high := 0, len(records)
+	for low < high {
+		mid := low + (high-low)/2
+		if k := records[mid].count; key < k {
+			high = mid
+		} else if key > k {
+			low = mid + 1
+		} else {
+			return mid, true
+		}
+	}
+	return -1, false
+}
+
+// validate checks the constraints declared on the fields.
+// It is called at the end of the parsing function.
+func (item *WithConstraints) validate(numGlyphs int) error {
+	if item.format != 1 && item.format != 2 && item.format != 4 {
+		return validationError{path: "WithConstraints.format", constraint: "one of 1, 2, 4", value: item.format}
+	}
+	if item.unitsPerEm < 16 {
+		return validationError{path: "WithConstraints.unitsPerEm", constraint: ">= 16", value: item.unitsPerEm}
+	}
+	if item.unitsPerEm > 16384 {
+		return validationError{path: "WithConstraints.unitsPerEm", constraint: "<= 16384", value: item.unitsPerEm}
+	}
+	if item.count == 0 {
+		return validationError{path: "WithConstraints.count", constraint: "non zero", value: item.count}
+	}
+	if int(item.defaultGlyph) >= int(numGlyphs) {
+		return validationError{path: "WithConstraints.defaultGlyph", constraint: "< numGlyphs", value: item.defaultGlyph}
+	}
+	for i, v := range item.glyphs {
+		if int(v) >= int(numGlyphs) {
+			return validationError{path: fmt.Sprintf("WithConstraints.glyphs[%d]", i), constraint: "< numGlyphs", value: v}
+		}
+	}
+	for i, v := range item.classes {
+		if v < -1 {
+			return validationError{path: fmt.Sprintf("WithConstraints.classes[%d]", i), constraint: ">= -1", value: v}
+		}
+		if v > 1 {
+			return validationError{path: fmt.Sprintf("WithConstraints.classes[%d]", i), constraint: "<= 1", value: v}
+		}
+	}
+	return nil
+}
+
+func (item *WithFixedReserved) mustParse(src []byte) {
+	_ = src[9] // early bound checking
+	item.format = binary.BigEndian.Uint16(src[0:])
+
+	{
+		bits := src[6]
+		item.level = uint8((bits >> 4) & 0xf)
+	}
+
+	item.value = int16(binary.BigEndian.Uint16(src[8:]))
+}
+
+func (item *WithFixedSkipped) mustParse(src []byte) {
+	_ = src[5] // early bound checking
+	item.a = binary.BigEndian.Uint16(src[0:])
+	item.b = binary.BigEndian.Uint32(src[2:])
+}
+
+// FindGlyphs returns the index of the record in [glyphs] whose glyph is [key],
+// using a binary search.
+func (item *WithSortedRecords) FindGlyphs(key uint16) (int, bool) {
+	records := item.glyphs
+	low, high := 0, len(records)
+	for low < high {
+		mid := low + (high-low)/2
+		if k := records[mid].glyph; key < k {
+			high = mid
+		} else if key > k {
+			low = mid + 1
+		} else {
+			return mid, true
+		}
+	}
+	return -1, false
+}
+
+// FindKinds returns the index of the record in [kinds] whose start is [key],
+// using a binary search.
+func (item *WithSortedRecords) FindKinds(key uint16) (int, bool) {
+	records := item.kinds
+	low, high := 0, len(records)
+	for low < high {
+		mid := low + (high-low)/2
+		if k := records[mid].start; key < k {
+			high = mid
+		} else if key > k {
+			low = mid + 1
+		} else {
+			return mid, true
+		}
+	}
+	return -1, false
+}
+
+// FindRanges returns the index of the record in [ranges] whose range [start, end]
+// contains [key], using a binary search.
+func (item *WithSortedRecords) FindRanges(key uint16) (int, bool) {
+	records := item.ranges
+	low, high := 0, len(records)
+	for low < high {
+		mid := low + (high-low)/2
+		if key < records[mid].start {
+			high = mid
+		} else if key > records[mid].end {
+			low = mid + 1
+		} else {
+			return mid, true
+		}
+	}
+	return -1, false
+}
+
+// computeAdler returns the checksum of the data described by [adler],
+// using [src] to resolve the range.
+func (item *WithTableChecksums) computeAdler(src []byte) (uint32, error) {
+	// the checksum field itself is considered to be zero
+	if len(src) < 10 {
+		return 0, fmt.Errorf("EOF: expected length: 10, got %d", len(src))
+	}
+	return checksumAdler32Zeroed(src, 6), nil
+}
+
+// computeSum returns the checksum of the data described by [sum],
+// using [src] to resolve the range.
+func (item *WithTableChecksums) computeSum(src []byte) (uint32, error) {
+	// the checksum field itself is considered to be zero
+	if len(src) < 6 {
+		return 0, fmt.Errorf("EOF: expected length: 6, got %d", len(src))
+	}
+	return checksumOpenTypeZeroed(src, 2), nil
+}
+
+// verifyChecksums checks that the fields storing a checksum
+// match their data, using [src] to resolve the ranges.
+// It is not called by the parsing function.
+func (item *WithTableChecksums) verifyChecksums(src []byte) error {
+	if got, err := item.computeSum(src); err != nil {
+		return err
+	} else if got != uint32(item.sum) {
+		return checksumError{field: "WithTableChecksums.sum", expected: uint32(item.sum), got: got}
+	}
+	if got, err := item.computeAdler(src); err != nil {
+		return err
+	} else if got != uint32(item.adler) {
+		return checksumError{field: "WithTableChecksums.adler", expected: uint32(item.adler), got: got}
+	}
+	return nil
+}
+
+// checksumAdler32Zeroed returns the Adler32 checksum of [data],
+// as if the 4 bytes at [field] were zero.
+func checksumAdler32Zeroed(data []byte, field int) uint32 {
+	const (
+		mod  = 65521
+		nmax = 5552 // the sums do not overflow for nmax bytes
+	)
+	a, b := uint32(1), uint32(0)
+	for i, c := range data {
+		if field <= i && i < field+4 {
+			c = 0
+		}
+		a += uint32(c)
+		b += a
+		if i%nmax == nmax-1 {
+			a, b = a%mod, b%mod
+		}
+	}
+	return (b%mod)<<16 | a%mod
+}
+
+// checksumZeroWord is used in place of a checksum field
+var checksumZeroWord [4]byte
+
+// checksumCRC32Zeroed returns the CRC32 checksum of [data],
+// as if the 4 bytes at [field] were zero.
+func checksumCRC32Zeroed(data []byte, field int) uint32 {
+	sum := crc32.ChecksumIEEE(data[:field])
+	sum = crc32.Update(sum, crc32.IEEETable, checksumZeroWord[:])
+	return crc32.Update(sum, crc32.IEEETable, data[field+4:])
+}
+
+// checksumError is returned when a checksum does not
+// match its data
+type checksumError struct {
+	field    string
+	expected uint32 // as stored in the field
+	got      uint32 // as computed from the data
+}
+
+func (err checksumError) Error() string {
+	return fmt.Sprintf("invalid checksum for %s: expected 0x%08x, got 0x%08x", err.field, err.expected, err.got)
+}
+
+// checksumOpenType returns the sum of [data] read as uint32,
+// padded with zeros if needed.
+func checksumOpenType(data []byte) uint32 {
+	var sum uint32
+	for ; len(data) >= 4; data = data[4:] {
+		sum += binary.BigEndian.Uint32(data)
+	}
+	if len(data) != 0 {
+		var tail [4]byte
+		copy(tail[:], data)
+		sum += binary.BigEndian.Uint32(tail[:])
+	}
+	return sum
+}
+
+// checksumOpenTypeZeroed returns the checksum of [data],
+// as if the 4 bytes at [field] were zero.
+func checksumOpenTypeZeroed(data []byte, field int) uint32 {
+	sum := checksumOpenType(data)
+	for i := field; i < field+4; i++ {
+		sum -= uint32(data[i]) << (8 * (3 - i%4))
+	}
+	return sum
+}
+
+// compressionError is returned when the uncompressed data
+// does not match the expected length
+type compressionError struct {
+	algorithm string
+	expected  int
+	exceeded  bool
+}
+
+func (err compressionError) Error() string {
+	if err.exceeded {
+		return fmt.Sprintf("%s data exceeds the expected length %d", err.algorithm, err.expected)
+	}
+	return fmt.Sprintf("%s data does not match the expected length %d", err.algorithm, err.expected)
+}
+
+// validate checks the constraints declared on the fields.
+// It is called at the end of the parsing function.
+func (item *constrainedRecord) validate(numGlyphs int) error {
+	if int(item.glyph) >= int(numGlyphs) {
+		return validationError{path: "constrainedRecord.glyph", constraint: "< numGlyphs", value: item.glyph}
+	}
+	if item.class > 3 {
+		return validationError{path: "constrainedRecord.class", constraint: "<= 3", value: item.class}
+	}
+	return nil
+}
+
+// decodeASCII checks that [data] only contains ASCII characters
+func decodeASCII(data []byte) (string, error) {
+	for i, b := range data {
+		if b >= 0x80 {
+			return "", stringError{encoding: "ASCII", index: i}
+		}
+	}
+	return string(data), nil
+}
+
+// decodeMacRoman decodes [data], which is always valid
+func decodeMacRoman(data []byte) (string, error) {
+	out := make([]rune, len(data))
+	for i, b := range data {
+		if b < 0x80 {
+			out[i] = rune(b)
+		} else {
+			out[i] = macRomanHigh[b-0x80]
+		}
+	}
+	return string(out), nil
+}
+
+// decodeUTF16BE decodes [data], rejecting invalid surrogates
+func decodeUTF16BE(data []byte) (string, error) {
+	if len(data)%2 != 0 {
+		return "", stringError{encoding: "UTF-16BE", index: len(data) - 1}
+	}
+	var out strings.Builder
+	for i := 0; i < len(data); i += 2 {
+		u := rune(data[i])<<8 | rune(data[i+1])
+		switch {
+		case 0xD800 <= u && u < 0xDC00: // high surrogate
+			if i+4 > len(data) {
+				return "", stringError{encoding: "UTF-16BE", index: i}
+			}
+			low := rune(data[i+2])<<8 | rune(data[i+3])
+			if !(0xDC00 <= low && low < 0xE000) {
+				return "", stringError{encoding: "UTF-16BE", index: i}
+			}
+			out.WriteRune(utf16.DecodeRune(u, low))
+			i += 2
+		case 0xDC00 <= u && u < 0xE000: // unpaired low surrogate
+			return "", stringError{encoding: "UTF-16BE", index: i}
+		default:
+			out.WriteRune(u)
+		}
+	}
+	return out.String(), nil
+}
+
+// decodeUTF8 checks that [data] is valid UTF-8
+func decodeUTF8(data []byte) (string, error) {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return "", stringError{encoding: "UTF-8", index: i}
+		}
+		i += size
+	}
+	return string(data), nil
+}
+
+// expectError is returned when a field does not
+// have one of its expected values
+type expectError struct {
+	field    string
+	expected string
+	got      interface{}
+}
+
+func (err expectError) Error() string {
+	return fmt.Sprintf("invalid value for %s: expected %s, got %#x", err.field, err.expected, err.got)
+}
+
+func (item *glyphRecord) mustParse(src []byte) {
+	_ = src[3] // early bound checking
+	item.glyph = binary.BigEndian.Uint16(src[0:])
+	item.class = binary.BigEndian.Uint16(src[2:])
+}
+
+// inflateDeflate decompresses the deflate stream at the start of [src], which
+// must have [length] bytes once uncompressed.
+// It returns the number of compressed bytes read.
+func inflateDeflate(src []byte, length int) ([]byte, int, error) {
+	r := bytes.NewReader(src)
+	out, err := readInflated(flate.NewReader(r), length, "deflate")
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, len(src) - r.Len(), nil
+}
+
+// inflateGzip decompresses the gzip stream at the start of [src], which
+// must have [length] bytes once uncompressed.
+// It returns the number of compressed bytes read.
+func inflateGzip(src []byte, length int) ([]byte, int, error) {
+	r := bytes.NewReader(src)
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	zr.Multistream(false)
+	out, err := readInflated(zr, length, "gzip")
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, len(src) - r.Len(), nil
+}
+
+// inflateZlib decompresses the zlib stream at the start of [src], which
+// must have [length] bytes once uncompressed.
+// It returns the number of compressed bytes read.
+func inflateZlib(src []byte, length int) ([]byte, int, error) {
+	r := bytes.NewReader(src)
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	out, err := readInflated(zr, length, "zlib")
+	if err != nil {
+		return nil, 0, err
+	}
+	return out, len(src) - r.Len(), nil
+}
+
+func (item *lookupSubtable1) mustParse(src []byte) {
+	_ = src[3] // early bound checking
+	item.format = binary.BigEndian.Uint16(src[0:])
+	item.delta = int16(binary.BigEndian.Uint16(src[2:]))
+}
+
+// macRomanHigh maps the bytes 0x80 - 0xFF of the Mac OS Roman encoding
+var macRomanHigh = [128]rune{
+	0x00C4, 0x00C5, 0x00C7, 0x00C9, 0x00D1, 0x00D6, 0x00DC, 0x00E1,
+	0x00E0, 0x00E2, 0x00E4, 0x00E3, 0x00E5, 0x00E7, 0x00E9, 0x00E8,
+	0x00EA, 0x00EB, 0x00ED, 0x00EC, 0x00EE, 0x00EF, 0x00F1, 0x00F3,
+	0x00F2, 0x00F4, 0x00F6, 0x00F5, 0x00FA, 0x00F9, 0x00FB, 0x00FC,
+	0x2020, 0x00B0, 0x00A2, 0x00A3, 0x00A7, 0x2022, 0x00B6, 0x00DF,
+	0x00AE, 0x00A9, 0x2122, 0x00B4, 0x00A8, 0x2260, 0x00C6, 0x00D8,
+	0x221E, 0x00B1, 0x2264, 0x2265, 0x00A5, 0x00B5, 0x2202, 0x2211,
+	0x220F, 0x03C0, 0x222B, 0x00AA, 0x00BA, 0x03A9, 0x00E6, 0x00F8,
+	0x00BF, 0x00A1, 0x00AC, 0x221A, 0x0192, 0x2248, 0x2206, 0x00AB,
+	0x00BB, 0x2026, 0x00A0, 0x00C0, 0x00C3, 0x00D5, 0x0152, 0x0153,
+	0x2013, 0x2014, 0x201C, 0x201D, 0x2018, 0x2019, 0x00F7, 0x25CA,
+	0x00FF, 0x0178, 0x2044, 0x20AC, 0x2039, 0x203A, 0xFB01, 0xFB02,
+	0x2021, 0x00B7, 0x201A, 0x201E, 0x2030, 0x00C2, 0x00CA, 0x00C1,
+	0x00CB, 0x00C8, 0x00CD, 0x00CE, 0x00CF, 0x00CC, 0x00D3, 0x00D4,
+	0xF8FF, 0x00D2, 0x00DA, 0x00DB, 0x00D9, 0x0131, 0x02C6, 0x02DC,
+	0x00AF, 0x02D8, 0x02D9, 0x02DA, 0x00B8, 0x02DD, 0x02DB, 0x02C7,
+}
+
+// nestedValidationError prefixes the path of the [validationError]
+// wrapped in [err], if any, with [parent], the path of the field storing
+// the nested value. The other errors are returned unchanged.
+func nestedValidationError(err error, parent string) error {
+	var ve validationError
+	if !errors.As(err, &ve) {
+		return err
+	}
+	if i := strings.IndexByte(ve.path, '.'); i != -1 { // remove the nested type name
+		ve.path = parent + ve.path[i:]
+	}
+	return ve
+}
+
+func (v paintFormat) String() string {
+	switch v {
+	case paintSolid:
+		return "paintSolid"
+	case paintLinear:
+		return "paintLinear"
+	case paintRadial:
+		return "paintRadial"
+	case paintGlyph:
+		return "paintGlyph"
+	default:
+		return fmt.Sprintf("paintFormat(%d)", uint8(v))
+	}
+}
+
+func parseAxisRecord(src []byte) (axisRecord, int, error) {
+	var item axisRecord
+	n := 0
+	if L := len(src); L < 8 {
+		return item, 0, fmt.Errorf("reading axisRecord: "+"EOF: expected length: 8, got %d", L)
+	}
+	_ = src[7] // early bound checking
+	item.tag = opentype.Tag(binary.BigEndian.Uint32(src[0:]))
+	item.defaultValue = opentype.Fixed(binary.BigEndian.Uint32(src[4:]))
+	if err := item.tag.Validate(); err != nil {
+		return item, 0, fmt.Errorf("reading axisRecord: "+"invalid tag: %w", err)
+	}
+	n += 8
+
+	return item, n, nil
+}
+
+func parseClassDef(src []byte) (classDef, int, error) {
+	var item classDef
+	n := 0
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading classDef: "+"EOF: expected length: 6, got %d", L)
+	}
+	_ = src[5] // early bound checking
+	item.format = binary.BigEndian.Uint16(src[0:])
+	item.start = binary.BigEndian.Uint16(src[2:])
+	arrayLengthValues := int(binary.BigEndian.Uint16(src[4:]))
+
+	n += 6
+
+	{
+
+		if L := len(src); L < 6+arrayLengthValues*2 {
+			return item, 0, fmt.Errorf("reading classDef: "+"EOF: expected length: %d, got %d", 6+arrayLengthValues*2, L)
+		}
+
+		item.values = make([]uint16, arrayLengthValues) // allocation guarded by the previous check
+		for i := range item.values {
+			item.values[i] = binary.BigEndian.Uint16(src[6+i*2:])
+		}
+		n += arrayLengthValues * 2
+	}
+	return item, n, nil
+}
+
+func parseClassRecord(src []byte) (classRecord, int, error) {
+	var item classRecord
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading classRecord: "+"EOF: expected length: 4, got %d", L)
+	}
+	_ = src[3] // early bound checking
+	valueClass, err := markClassFromUint(binary.BigEndian.Uint16(src[0:]))
+	if err != nil {
+		return item, 0, fmt.Errorf("reading classRecord: "+"invalid class: %w", err)
+	}
+	item.class = valueClass
+	if err := item.name.fromUint(binary.BigEndian.Uint16(src[2:])); err != nil {
+		return item, 0, fmt.Errorf("reading classRecord: "+"invalid name: %w", err)
+	}
+
+	n += 4
+
+	return item, n, nil
+}
+
+func parseConstrainedRecord(src []byte, numGlyphs int) (constrainedRecord, int, error) {
+	var item constrainedRecord
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading constrainedRecord: "+"EOF: expected length: 4, got %d", L)
+	}
+	_ = src[3] // early bound checking
+	item.glyph = binary.BigEndian.Uint16(src[0:])
+	item.class = binary.BigEndian.Uint16(src[2:])
+
+	n += 4
+
+	if err := item.validate(numGlyphs); err != nil {
+		return item, 0, fmt.Errorf("reading constrainedRecord: %w", err)
+	}
+	return item, n, nil
+}
+
+func parseDateRecord(src []byte) (dateRecord, int, error) {
+	var item dateRecord
+	n := 0
+	if L := len(src); L < 10 {
+		return item, 0, fmt.Errorf("reading dateRecord: "+"EOF: expected length: 10, got %d", L)
+	}
+	_ = src[9] // early bound checking
+	valueDate, _, err := parseDate(src[0:])
+	if err != nil {
+		return item, 0, fmt.Errorf("reading dateRecord: "+"invalid date: %w", err)
+	}
+	item.date = valueDate
+	item.count = binary.BigEndian.Uint16(src[8:])
+
+	n += 10
+
+	return item, n, nil
+}
+
+func parseEnumRecord(src []byte) (enumRecord, int, error) {
+	var item enumRecord
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading enumRecord: "+"EOF: expected length: 2, got %d", L)
+	}
+	_ = src[1] // early bound checking
+	item.format = paintFormat(src[0])
+	item.hidden = src[1] != 0
+	switch item.format {
+	case paintSolid, paintLinear, paintRadial, paintGlyph:
+	default:
+		return item, 0, fmt.Errorf("reading enumRecord: %w", expectError{field: "enumRecord.format", expected: "paintSolid or paintLinear or paintRadial or paintGlyph", got: uint8(item.format)})
+	}
+	if b := src[1]; b > 1 {
+		return item, 0, fmt.Errorf("reading enumRecord: %w", expectError{field: "enumRecord.hidden", expected: "0x0 or 0x1", got: b})
+	}
+	n += 2
+
+	return item, n, nil
+}
+
+func parseHookedElement(src []byte) (hookedElement, int, error) {
+	var item hookedElement
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading hookedElement: "+"EOF: expected length: 2, got %d", L)
+	}
+	item.a = binary.BigEndian.Uint16(src[0:])
+
+	n += 2
+
+	if err := item.afterA(); err != nil {
+		return item, 0, fmt.Errorf("reading hookedElement: %w", err)
+	}
+	return item, n, nil
+}
+
+func parseKindRecord(src []byte) (kindRecord, int, error) {
+	var item kindRecord
+	n := 0
+	if L := len(src); L < 3 {
+		return item, 0, fmt.Errorf("reading kindRecord: "+"EOF: expected length: 3, got %d", L)
+	}
+	_ = src[2] // early bound checking
+	item.start = binary.BigEndian.Uint16(src[0:])
+	item.kind = extendMode(src[2])
+	switch item.kind {
+	case extendPad, extendRepeat, extendReflect:
+	default:
+		return item, 0, fmt.Errorf("reading kindRecord: %w", expectError{field: "kindRecord.kind", expected: "extendPad or extendRepeat or extendReflect", got: uint8(item.kind)})
+	}
+	n += 3
+
+	return item, n, nil
+}
+
+func parseLookupSubtable1(src []byte) (lookupSubtable1, int, error) {
+	var item lookupSubtable1
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading lookupSubtable1: "+"EOF: expected length: 4, got %d", L)
+	}
+	item.mustParse(src)
+	n += 4
+	return item, n, nil
+}
+
+func parseLookupSubtable2(src []byte) (lookupSubtable2, int, error) {
+	var item lookupSubtable2
+	n := 0
+	if L := len(src); L < 2 {
+		return item, 0, fmt.Errorf("reading lookupSubtable2: "+"EOF: expected length: 2, got %d", L)
+	}
+	arrayLengthValues := int(binary.BigEndian.Uint16(src[0:]))
+
+	n += 2
+
+	{
+
+		if L := len(src); L < 2+arrayLengthValues*2 {
+			return item, 0, fmt.Errorf("reading lookupSubtable2: "+"EOF: expected length: %d, got %d", 2+arrayLengthValues*2, L)
+		}
+
+		item.values = make([]uint16, arrayLengthValues) // allocation guarded by the previous check
+		for i := range item.values {
+			item.values[i] = binary.BigEndian.Uint16(src[2+i*2:])
+		}
+		n += arrayLengthValues * 2
+	}
+	return item, n, nil
+}
+
+func parseLookupSubtableExtension(src []byte) (lookupSubtableExtension, int, error) {
+	var item lookupSubtableExtension
+	n := 0
+	if L := len(src); L < 8 {
+		return item, 0, fmt.Errorf("reading lookupSubtableExtension: "+"EOF: expected length: 8, got %d", L)
+	}
+	_ = src[7] // early bound checking
+	item.format = binary.BigEndian.Uint16(src[0:])
+	item.kind = lookupVersion(binary.BigEndian.Uint16(src[2:]))
+	offsetInner := int(binary.BigEndian.Uint32(src[4:]))
+	if item.format != 1 {
+		return item, 0, fmt.Errorf("reading lookupSubtableExtension: %w", expectError{field: "lookupSubtableExtension.format", expected: "0x1", got: item.format})
+	}
+	n += 8
+
+	{
+
+		if offsetInner != 0 { // ignore null offset
+			if L := len(src); L < offsetInner {
+				return item, 0, fmt.Errorf("reading lookupSubtableExtension: "+"EOF: expected length: %d, got %d", offsetInner, L)
+			}
+
+			var (
+				read int
+				err  error
+			)
+			switch item.kind {
+			case lookupVersion1:
+				item.inner, read, err = parseLookupSubtable1(src[offsetInner:])
+			case lookupVersion2:
+				item.inner, read, err = parseLookupSubtable2(src[offsetInner:])
+			default:
+				err = fmt.Errorf("unsupported lookupSubtableVersion %d", item.kind)
+			}
+			if err != nil {
+				return item, 0, fmt.Errorf("reading lookupSubtableExtension: %w", err)
+			}
+			offsetInner += read
 		}
-		item.currentToOffset = src[n:L]
-		n = L
 	}
 	return item, n, nil
 }
 
-func ParseWithSlices(src []byte) (WithSlices, int, error) {
-	var item WithSlices
+func parseScriptRecord(src []byte, parentSrc []byte) (scriptRecord, int, error) {
+	var item scriptRecord
 	n := 0
-	if L := len(src); L < 2 {
-		return item, 0, fmt.Errorf("reading WithSlices: "+"EOF: expected length: 2, got %d", L)
+	if L := len(src); L < 6 {
+		return item, 0, fmt.Errorf("reading scriptRecord: "+"EOF: expected length: 6, got %d", L)
 	}
-	item.length = binary.BigEndian.Uint16(src[0:])
-	n += 2
+	_ = src[5] // early bound checking
+	item.tag = Tag(binary.BigEndian.Uint32(src[0:]))
+	offsetScript := int(binary.BigEndian.Uint16(src[4:]))
+
+	n += 6
 
 	{
-		arrayLength := int(item.length)
 
-		offset := 2
-		for i := 0; i < arrayLength; i++ {
-			elem, read, err := parseVarSize(src[offset:])
+		if offsetScript != 0 { // ignore null offset
+			if L := len(parentSrc); L < offsetScript {
+				return item, 0, fmt.Errorf("reading scriptRecord: "+"EOF: expected length: %d, got %d", offsetScript, L)
+			}
+
+			var err error
+			item.script, _, err = ParseWithArray(parentSrc[offsetScript:])
 			if err != nil {
-				return item, 0, fmt.Errorf("reading WithSlices: %s", err)
+				return item, 0, fmt.Errorf("reading scriptRecord: %w", err)
 			}
-			item.s1 = append(item.s1, elem)
-			offset += read
+
 		}
-		n = offset
 	}
 	return item, n, nil
 }
 
-func ParseWithUnion(src []byte) (WithUnion, int, error) {
-	var item WithUnion
+func parseStrictRecord(src []byte) (strictRecord, int, error) {
+	var item strictRecord
 	n := 0
-	if L := len(src); L < 3 {
-		return item, 0, fmt.Errorf("reading WithUnion: "+"EOF: expected length: 3, got %d", L)
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading strictRecord: "+"EOF: expected length: 4, got %d", L)
 	}
-	_ = src[2] // early bound checking
-	item.version = subtableFlagVersion(binary.BigEndian.Uint16(src[0:]))
-	item.otherField = src[2]
-	n += 3
+	_ = src[3] // early bound checking
+	item.value = binary.BigEndian.Uint16(src[0:])
 
-	{
-		var (
-			read int
-			err  error
-		)
-		switch item.version {
-		case subtableFlagVersion1:
-			item.data, read, err = parseSubtableITF1(src[3:])
-		case subtableFlagVersion2:
-			item.data, read, err = parseSubtableITF2(src[3:])
-		default:
-			err = fmt.Errorf("unsupported subtableITFVersion %d", item.version)
-		}
-		if err != nil {
-			return item, 0, fmt.Errorf("reading WithUnion: %s", err)
+	for i, b := range src[2:4] {
+		if b != 0 {
+			return item, 0, fmt.Errorf("reading strictRecord: "+"non zero reserved byte at %d", 2+i)
 		}
-		n += read
 	}
-	return item, n, nil
-}
-
-func (item *WithAlias) mustParse(src []byte) {
-	item.f = fl32FromUint(binary.BigEndian.Uint32(src[0:]))
-}
+	n += 4
 
-func (item *WithArray) mustParse(src []byte) {
-	_ = src[20] // early bound checking
-	item.a = binary.BigEndian.Uint16(src[0:])
-	item.b[0] = binary.BigEndian.Uint32(src[2:])
-	item.b[1] = binary.BigEndian.Uint32(src[6:])
-	item.b[2] = binary.BigEndian.Uint32(src[10:])
-	item.b[3] = binary.BigEndian.Uint32(src[14:])
-	item.c[0] = src[18]
-	item.c[1] = src[19]
-	item.c[2] = src[20]
+	return item, n, nil
 }
 
 func parseSubtableITF1(src []byte) (subtableITF1, int, error) {
@@ -660,6 +3635,7 @@ func parseToBeEmbeded(src []byte) (toBeEmbeded, int, error) {
 	item.a = src[0]
 	item.b = src[1]
 	arrayLengthC := int(binary.BigEndian.Uint16(src[2:]))
+
 	n += 4
 
 	{
@@ -677,6 +3653,181 @@ func parseToBeEmbeded(src []byte) (toBeEmbeded, int, error) {
 	return item, n, nil
 }
 
+func parseUniformRecord(src []byte, parentSrc []byte, indicesCount int, bitsCount int, dataCount int, valueFormat uint16, width uint8) (uniformRecord, int, error) {
+	var item uniformRecord
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading uniformRecord: "+"EOF: expected length: 4, got %d", L)
+	}
+	_ = src[3] // early bound checking
+	item.glyph = binary.BigEndian.Uint16(src[0:])
+	offsetV := int(binary.BigEndian.Uint16(src[2:]))
+
+	n += 4
+
+	{
+
+		if offsetV != 0 { // ignore null offset
+			if L := len(parentSrc); L < offsetV {
+				return item, 0, fmt.Errorf("reading uniformRecord: "+"EOF: expected length: %d, got %d", offsetV, L)
+			}
+
+			var err error
+			item.v, _, err = parseVarSize(parentSrc[offsetV:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading uniformRecord: %w", err)
+			}
+
+		}
+	}
+	{
+
+		width := int(width)
+		if indicesCount != 0 && (width < 1 || width > 4) {
+			return item, 0, fmt.Errorf("reading uniformRecord: "+"invalid byte width %d", width)
+		}
+		if L := len(src); L < 4+indicesCount*width {
+			return item, 0, fmt.Errorf("reading uniformRecord: "+"EOF: expected length: %d, got %d", 4+indicesCount*width, L)
+		}
+
+		item.indices = make([]uint32, indicesCount) // allocation guarded by the previous check
+		for i := range item.indices {
+			switch width {
+			case 1:
+				item.indices[i] = uint32(src[4+i*width])
+			case 2:
+				item.indices[i] = uint32(binary.BigEndian.Uint16(src[4+i*width:]))
+			case 3:
+				item.indices[i] = uint32(src[4+i*width+0])<<16 | uint32(src[4+i*width+1])<<8 | uint32(src[4+i*width+2])
+			case 4:
+				item.indices[i] = binary.BigEndian.Uint32(src[4+i*width:])
+			}
+		}
+		n += indicesCount * width
+	}
+	{
+
+		bitWidth := int(width)
+		if bitWidth < 1 || bitWidth > 8 {
+			return item, 0, fmt.Errorf("reading uniformRecord: "+"invalid bit width %d", bitWidth)
+		}
+		byteLength := (bitsCount*bitWidth + 7) / 8
+		if L := len(src); L < n+byteLength {
+			return item, 0, fmt.Errorf("reading uniformRecord: "+"EOF: expected length: %d, got %d", n+byteLength, L)
+		}
+		item.bits = make([]uint8, bitsCount) // allocation guarded by the previous check
+		for i := range item.bits {
+			var v uint8
+			for b := i * bitWidth; b < (i+1)*bitWidth; b++ {
+				v = v<<1 | uint8(src[n+b/8]>>(7-b%8))&1
+			}
+			item.bits[i] = v
+		}
+		n += byteLength
+	}
+	{
+
+		L := int(n + dataCount)
+		if len(src) < L {
+			return item, 0, fmt.Errorf("reading uniformRecord: "+"EOF: expected length: %d, got %d", L, len(src))
+		}
+		item.data = src[n:L]
+		n = L
+	}
+	{
+		var (
+			err  error
+			read int
+		)
+		item.value, read, err = parseValueRecord(src[n:], src, valueFormat)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading uniformRecord: %w", err)
+		}
+		n += read
+	}
+	return item, n, nil
+}
+
+func parseValueRecord(src []byte, parentSrc []byte, valueFormat uint16) (valueRecord, int, error) {
+	var item valueRecord
+	n := 0
+	hasXPlacement := valueFormat&0x0001 != 0
+	hasYPlacement := valueFormat&0x0002 != 0
+	hasXAdvance := valueFormat&0x0004 != 0
+	hasYAdvance := valueFormat&0x0008 != 0
+	hasXPlaDevice := valueFormat&0x0010 != 0
+	var offsetXPlaDevice int
+	{
+		expectedLength := 0
+		if hasXPlacement {
+			expectedLength += 2
+		}
+		if hasYPlacement {
+			expectedLength += 2
+		}
+		if hasXAdvance {
+			expectedLength += 2
+		}
+		if hasYAdvance {
+			expectedLength += 2
+		}
+		if hasXPlaDevice {
+			expectedLength += 2
+		}
+		if L := len(src); L < expectedLength {
+			return item, 0, fmt.Errorf("reading valueRecord: "+"EOF: expected length: %d, got %d", expectedLength, L)
+		}
+	}
+
+	if hasXPlacement {
+		item.xPlacement = int16(binary.BigEndian.Uint16(src[0:]))
+
+		n += 2
+
+	}
+	if hasYPlacement {
+		item.yPlacement = int16(binary.BigEndian.Uint16(src[n:]))
+
+		n += 2
+
+	}
+	if hasXAdvance {
+		item.xAdvance = int16(binary.BigEndian.Uint16(src[n:]))
+
+		n += 2
+
+	}
+	if hasYAdvance {
+		item.yAdvance = int16(binary.BigEndian.Uint16(src[n:]))
+
+		n += 2
+
+	}
+	if hasXPlaDevice {
+		offsetXPlaDevice = int(binary.BigEndian.Uint16(src[n:]))
+		n += 2
+	}
+	{
+
+		if offsetXPlaDevice != 0 { // ignore null offset
+			if L := len(parentSrc); L < offsetXPlaDevice {
+				return item, 0, fmt.Errorf("reading valueRecord: "+"EOF: expected length: %d, got %d", offsetXPlaDevice, L)
+			}
+
+			var (
+				err  error
+				read int
+			)
+			item.xPlaDevice, read, err = ParseDeviceTable(parentSrc[offsetXPlaDevice:])
+			if err != nil {
+				return item, 0, fmt.Errorf("reading valueRecord: %w", err)
+			}
+			offsetXPlaDevice += read
+		}
+	}
+	return item, n, nil
+}
+
 func parseVarSize(src []byte) (varSize, int, error) {
 	var item varSize
 	n := 0
@@ -686,6 +3837,7 @@ func parseVarSize(src []byte) (varSize, int, error) {
 	_ = src[5] // early bound checking
 	item.f1 = binary.BigEndian.Uint32(src[0:])
 	arrayLengthArray := int(binary.BigEndian.Uint16(src[4:]))
+
 	n += 6
 
 	{
@@ -704,6 +3856,7 @@ func parseVarSize(src []byte) (varSize, int, error) {
 		return item, 0, fmt.Errorf("reading varSize: "+"EOF: expected length: n + 4, got %d", L)
 	}
 	arrayLengthStucts := int(binary.BigEndian.Uint32(src[n:]))
+
 	n += 4
 
 	{
@@ -718,12 +3871,13 @@ func parseVarSize(src []byte) (varSize, int, error) {
 		}
 		n += arrayLengthStucts * 4
 	}
-	var err error
-	n, err = item.parseEnd(src)
-	if err != nil {
-		return item, 0, fmt.Errorf("reading varSize: %s", err)
+	{
+		var err error
+		n, err = item.parseEnd(src)
+		if err != nil {
+			return item, 0, fmt.Errorf("reading varSize: %w", err)
+		}
 	}
-
 	return item, n, nil
 }
 
@@ -745,8 +3899,184 @@ func parseWithArgument(src []byte, arrayCount int, kind uint16, version uint16)
 	return item, n, nil
 }
 
+func parseWithFixedHooks(src []byte, numGlyphs int) (withFixedHooks, int, error) {
+	var item withFixedHooks
+	n := 0
+	if L := len(src); L < 4 {
+		return item, 0, fmt.Errorf("reading withFixedHooks: "+"EOF: expected length: 4, got %d", L)
+	}
+	_ = src[3] // early bound checking
+	item.a = binary.BigEndian.Uint16(src[0:])
+	item.b = binary.BigEndian.Uint16(src[2:])
+
+	n += 4
+
+	if err := item.afterB(numGlyphs); err != nil {
+		return item, 0, fmt.Errorf("reading withFixedHooks: %w", err)
+	}
+	if err := item.validate(numGlyphs); err != nil {
+		return item, 0, fmt.Errorf("reading withFixedHooks: %w", err)
+	}
+	return item, n, nil
+}
+
+func (item *rangeRecord) mustParse(src []byte) {
+	_ = src[5] // early bound checking
+	item.start = binary.BigEndian.Uint16(src[0:])
+	item.end = binary.BigEndian.Uint16(src[2:])
+	item.startIndex = binary.BigEndian.Uint16(src[4:])
+}
+
+// read255UInt16 decodes a WOFF2 255UInt16, returning the number of bytes read.
+func read255UInt16(src []byte) (uint16, int, error) {
+	const (
+		oneMoreByteCode1 = 255
+		oneMoreByteCode2 = 254
+		wordCode         = 253
+		lowestUCode      = 253
+	)
+	if len(src) < 1 {
+		return 0, 0, fmt.Errorf("EOF: expected length: 1, got %d", len(src))
+	}
+	code := src[0]
+	switch code {
+	case wordCode:
+		if len(src) < 3 {
+			return 0, 0, fmt.Errorf("EOF: expected length: 3, got %d", len(src))
+		}
+		value := uint16(src[1])<<8 | uint16(src[2])
+		if value < 2*lowestUCode+256 {
+			return 0, 0, varIntError{encoding: "255UInt16", reason: "non canonical encoding"}
+		}
+		return value, 3, nil
+	case oneMoreByteCode1, oneMoreByteCode2:
+		if len(src) < 2 {
+			return 0, 0, fmt.Errorf("EOF: expected length: 2, got %d", len(src))
+		}
+		if code == oneMoreByteCode2 {
+			return uint16(src[1]) + 2*lowestUCode, 2, nil
+		}
+		if src[1] >= lowestUCode {
+			return 0, 0, varIntError{encoding: "255UInt16", reason: "non canonical encoding"}
+		}
+		return uint16(src[1]) + lowestUCode, 2, nil
+	default:
+		return uint16(code), 1, nil
+	}
+}
+
+// readCFFInteger decodes a CFF DICT integer operand, returning the number of bytes read.
+func readCFFInteger(src []byte) (int32, int, error) {
+	if len(src) < 1 {
+		return 0, 0, fmt.Errorf("EOF: expected length: 1, got %d", len(src))
+	}
+	b0 := src[0]
+	switch {
+	case 32 <= b0 && b0 <= 246:
+		return int32(b0) - 139, 1, nil
+	case 247 <= b0 && b0 <= 254:
+		if len(src) < 2 {
+			return 0, 0, fmt.Errorf("EOF: expected length: 2, got %d", len(src))
+		}
+		if b0 <= 250 {
+			return (int32(b0)-247)*256 + int32(src[1]) + 108, 2, nil
+		}
+		return -(int32(b0)-251)*256 - int32(src[1]) - 108, 2, nil
+	case b0 == 28:
+		if len(src) < 3 {
+			return 0, 0, fmt.Errorf("EOF: expected length: 3, got %d", len(src))
+		}
+		return int32(int16(binary.BigEndian.Uint16(src[1:]))), 3, nil
+	case b0 == 29:
+		if len(src) < 5 {
+			return 0, 0, fmt.Errorf("EOF: expected length: 5, got %d", len(src))
+		}
+		return int32(binary.BigEndian.Uint32(src[1:])), 5, nil
+	case b0 == 30:
+		return 0, 0, varIntError{encoding: "CFFOperand", reason: "unexpected real number"}
+	default:
+		return 0, 0, varIntError{encoding: "CFFOperand", reason: fmt.Sprintf("invalid operand byte %d", b0)}
+	}
+}
+
+// readCFFReal decodes a CFF DICT operand, either a real number or
+// an integer, returning the number of bytes read.
+func readCFFReal(src []byte) (float64, int, error) {
+	if len(src) < 1 || src[0] != 30 {
+		v, read, err := readCFFInteger(src)
+		return float64(v), read, err
+	}
+	var buf []byte
+	for i := 1; i < len(src); i++ {
+		for _, nibble := range [2]byte{src[i] >> 4, src[i] & 0xF} {
+			switch {
+			case nibble <= 9:
+				buf = append(buf, '0'+nibble)
+			case nibble == 0xa:
+				buf = append(buf, '.')
+			case nibble == 0xb:
+				buf = append(buf, 'E')
+			case nibble == 0xc:
+				buf = append(buf, 'E', '-')
+			case nibble == 0xe:
+				buf = append(buf, '-')
+			case nibble == 0xf:
+				v, err := strconv.ParseFloat(string(buf), 64)
+				if err != nil {
+					return 0, 0, varIntError{encoding: "CFFOperand", reason: "invalid real number " + string(buf)}
+				}
+				return v, i + 1, nil
+			default:
+				return 0, 0, varIntError{encoding: "CFFOperand", reason: "reserved nibble"}
+			}
+		}
+	}
+	return 0, 0, fmt.Errorf("EOF: unterminated real number")
+}
+
+// readInflated reads the whole content of [r], which must be exactly [length].
+// The memory used is bounded by the actual content and [length].
+func readInflated(r io.Reader, length int, algorithm string) ([]byte, error) {
+	if length < 0 {
+		return nil, compressionError{algorithm: algorithm, expected: length}
+	}
+	var buf bytes.Buffer
+	// read one more byte to detect data exceeding the expected length
+	_, err := io.Copy(&buf, io.LimitReader(r, int64(length)+1))
+	if err != nil {
+		return nil, err
+	}
+	if buf.Len() != length {
+		return nil, compressionError{algorithm: algorithm, expected: length, exceeded: buf.Len() > length}
+	}
+	return buf.Bytes(), nil
+}
+
+// readUIntBase128 decodes a WOFF2 UIntBase128, returning the number of bytes read.
+func readUIntBase128(src []byte) (uint32, int, error) {
+	var accum uint32
+	for i := 0; i < 5; i++ {
+		if len(src) <= i {
+			return 0, 0, fmt.Errorf("EOF: expected length: %d, got %d", i+1, len(src))
+		}
+		dataByte := src[i]
+		if i == 0 && dataByte == 0x80 {
+			return 0, 0, varIntError{encoding: "UIntBase128", reason: "leading zeros"}
+		}
+		// if any of the top 7 bits are set, << 7 would overflow
+		if accum&0xFE000000 != 0 {
+			return 0, 0, varIntError{encoding: "UIntBase128", reason: "overflow"}
+		}
+		accum = accum<<7 | uint32(dataByte&0x7F)
+		if dataByte&0x80 == 0 {
+			return accum, i + 1, nil
+		}
+	}
+	return 0, 0, varIntError{encoding: "UIntBase128", reason: "more than 5 bytes"}
+}
+
 func (item *singleScope) mustParse(src []byte) {
-	_ = src[52] // early bound checking
+	_ = src[50] // early bound checking
 	item.a = int32(binary.BigEndian.Uint32(src[0:]))
 	item.b = int32(binary.BigEndian.Uint32(src[4:]))
 	item.c = int32(binary.BigEndian.Uint32(src[8:]))
@@ -755,18 +4085,88 @@ func (item *singleScope) mustParse(src []byte) {
 	item.g = src[24]
 	item.h = src[25]
 	item.t = tag(binary.BigEndian.Uint32(src[26:]))
-	item.v = float214(binary.BigEndian.Uint32(src[30:]))
-	item.w = fl32FromUint(binary.BigEndian.Uint32(src[34:]))
-	item.array1[0] = src[38]
-	item.array1[1] = src[39]
-	item.array1[2] = src[40]
-	item.array1[3] = src[41]
-	item.array1[4] = src[42]
-	item.array2[0] = binary.BigEndian.Uint16(src[43:])
-	item.array2[1] = binary.BigEndian.Uint16(src[45:])
-	item.array2[2] = binary.BigEndian.Uint16(src[47:])
-	item.array2[3] = binary.BigEndian.Uint16(src[49:])
-	item.array2[4] = binary.BigEndian.Uint16(src[51:])
+	item.v.fromUint(binary.BigEndian.Uint16(src[30:]))
+	item.w = fl32FromUint(binary.BigEndian.Uint32(src[32:]))
+	item.array1[0] = src[36]
+	item.array1[1] = src[37]
+	item.array1[2] = src[38]
+	item.array1[3] = src[39]
+	item.array1[4] = src[40]
+	item.array2[0] = binary.BigEndian.Uint16(src[41:])
+	item.array2[1] = binary.BigEndian.Uint16(src[43:])
+	item.array2[2] = binary.BigEndian.Uint16(src[45:])
+	item.array2[3] = binary.BigEndian.Uint16(src[47:])
+	item.array2[4] = binary.BigEndian.Uint16(src[49:])
+}
+
+// sizeScriptRecord returns the size of a scriptRecord, which only depends on the arguments
+func sizeScriptRecord() int {
+	return 6
+}
+
+// sizeSubElement returns the size of a SubElement, which only depends on the arguments
+func sizeSubElement() int {
+	return 2
+}
+
+// sizeUniformRecord returns the size of a uniformRecord, which only depends on the arguments
+func sizeUniformRecord(indicesCount int, bitsCount int, dataCount int, valueFormat uint16, width uint8) int {
+	size := 4
+	size += indicesCount * int(width)
+	size += (bitsCount*(int(width)) + 7) / 8
+	size += dataCount
+	size += sizeValueRecord(valueFormat)
+	return size
+}
+
+// sizeValueRecord returns the size of a valueRecord, which only depends on the arguments
+func sizeValueRecord(valueFormat uint16) int {
+	size := 0
+	if valueFormat&0x0001 != 0 {
+		size += 2
+	}
+	if valueFormat&0x0002 != 0 {
+		size += 2
+	}
+	if valueFormat&0x0004 != 0 {
+		size += 2
+	}
+	if valueFormat&0x0008 != 0 {
+		size += 2
+	}
+	if valueFormat&0x0010 != 0 {
+		size += 2
+	}
+	return size
+}
+
+// sizeWithArgument returns the size of a withArgument, which only depends on the arguments
+func sizeWithArgument(arrayCount int, kind uint16, version uint16) int {
+	size := 0
+	size += arrayCount * 2
+	return size
+}
+
+// stringError is returned when the bytes of
+// a string are not valid for its encoding
+type stringError struct {
+	encoding string
+	index    int // in the string data
+}
+
+func (err stringError) Error() string {
+	return fmt.Sprintf("invalid %s data at byte %d", err.encoding, err.index)
+}
+
+func (v subtableFlagVersion) String() string {
+	switch v {
+	case subtableFlagVersion1:
+		return "subtableFlagVersion1"
+	case subtableFlagVersion2:
+		return "subtableFlagVersion2"
+	default:
+		return fmt.Sprintf("subtableFlagVersion(%d)", uint16(v))
+	}
 }
 
 func (item *subtableITF1) mustParse(src []byte) {
@@ -777,8 +4177,53 @@ func (item *subtableITF2) mustParse(src []byte) {
 	item.F = src[0]
 }
 
+func (item *tableRecord) mustParse(src []byte) {
+	_ = src[15] // early bound checking
+	item.tag = Tag(binary.BigEndian.Uint32(src[0:]))
+	item.checksum = binary.BigEndian.Uint32(src[4:])
+	item.offset = binary.BigEndian.Uint32(src[8:])
+	item.length = binary.BigEndian.Uint32(src[12:])
+}
+
+// trimZeroPadding removes the trailing zeros of [data],
+// for characters using [unitSize] bytes
+func trimZeroPadding(data []byte, unitSize int) []byte {
+	for len(data) >= unitSize {
+		for _, b := range data[len(data)-unitSize:] {
+			if b != 0 {
+				return data
+			}
+		}
+		data = data[:len(data)-unitSize]
+	}
+	return data
+}
+
+// validationError is returned when a field
+// does not satisfy its constraints
+type validationError struct {
+	path       string // <Type>.<field>, with an index for arrays, followed by the fields of nested structs
+	constraint string
+	value      interface{}
+}
+
+func (err validationError) Error() string {
+	return fmt.Sprintf("invalid value %v for %s: expected %s", err.value, err.path, err.constraint)
+}
+
+// varIntError is returned when a variable-length number is
+// invalid or not canonical
+type varIntError struct {
+	encoding string
+	reason   string
+}
+
+func (err varIntError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", err.encoding, err.reason)
+}
+
 func (item *withFixedSize) mustParse(src []byte) {
-	_ = src[52] // early bound checking
+	_ = src[50] // early bound checking
 	item.a = int32(binary.BigEndian.Uint32(src[0:]))
 	item.b = int32(binary.BigEndian.Uint32(src[4:]))
 	item.c = int32(binary.BigEndian.Uint32(src[8:]))
@@ -787,22 +4232,22 @@ func (item *withFixedSize) mustParse(src []byte) {
 	item.g = src[24]
 	item.h = src[25]
 	item.t = tag(binary.BigEndian.Uint32(src[26:]))
-	item.v = float214(binary.BigEndian.Uint32(src[30:]))
-	item.w = fl32FromUint(binary.BigEndian.Uint32(src[34:]))
-	item.array1[0] = src[38]
-	item.array1[1] = src[39]
-	item.array1[2] = src[40]
-	item.array1[3] = src[41]
-	item.array1[4] = src[42]
-	item.array2[0] = binary.BigEndian.Uint16(src[43:])
-	item.array2[1] = binary.BigEndian.Uint16(src[45:])
-	item.array2[2] = binary.BigEndian.Uint16(src[47:])
-	item.array2[3] = binary.BigEndian.Uint16(src[49:])
-	item.array2[4] = binary.BigEndian.Uint16(src[51:])
+	item.v.fromUint(binary.BigEndian.Uint16(src[30:]))
+	item.w = fl32FromUint(binary.BigEndian.Uint32(src[32:]))
+	item.array1[0] = src[36]
+	item.array1[1] = src[37]
+	item.array1[2] = src[38]
+	item.array1[3] = src[39]
+	item.array1[4] = src[40]
+	item.array2[0] = binary.BigEndian.Uint16(src[41:])
+	item.array2[1] = binary.BigEndian.Uint16(src[43:])
+	item.array2[2] = binary.BigEndian.Uint16(src[45:])
+	item.array2[3] = binary.BigEndian.Uint16(src[47:])
+	item.array2[4] = binary.BigEndian.Uint16(src[49:])
 }
 
 func (item *withFromExternalFile) mustParse(src []byte) {
-	_ = src[105] // early bound checking
+	_ = src[101] // early bound checking
 	item.a.mustParse(src[0:])
-	item.b.mustParse(src[53:])
+	item.b.mustParse(src[51:])
 }
@@ -1,5 +1,13 @@
 package testpackage
 
+import (
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/benoitkugler/binarygen/opentype"
+)
+
 // Used to test that aliases are correctly retrieved
 type WithAlias struct {
 	f fl32
@@ -195,3 +203,599 @@ type VariableThenFixed struct {
 	b uint32
 	c [5]byte
 }
+
+// Used to test implicit union tags which are not the first field
+type WithImplicitITFAtOffset struct {
+	device DeviceTable `offsetSize:"Offset16"`
+}
+
+type DeviceTable interface {
+	isDeviceTable()
+}
+
+func (DeviceTableHinting) isDeviceTable()   {}
+func (DeviceTableVariation) isDeviceTable() {}
+
+type DeviceTableHinting struct {
+	startSize   uint16
+	endSize     uint16
+	deltaFormat uint16   `unionTag:"1"`
+	deltaValues []uint16 `arrayCount:"ComputedField-endSize"`
+}
+
+type DeviceTableVariation struct {
+	deltaSetOuterIndex uint16
+	deltaSetInnerIndex uint16
+	deltaFormat        uint16 `unionTag:"0x8000"`
+}
+
+// Used to test fields packed in integers
+type WithBitfields struct {
+	lookupType      uint16
+	lookupFlag      uint16 `bits:"0-7"`
+	markAttachType  uint8  `bits:"8-15"`
+	subtableCount   uint16
+	rightToLeft     bool     `bits:"0"`
+	innerBitCount   uint8    `bits:"1-4"`
+	entrySize       uint8    `bits:"5-6"`
+	subtableOffsets []uint16 `arrayCount:"ComputedField-subtableCount"`
+}
+
+// Used to test consecutive packed integers
+type WithAdjacentBitfields struct {
+	deltaFormat uint16 `bits:"0-1"`
+	innerLevel  uint16 `bits:"2-15"`
+	outerLevel  uint16 `bits:"0-11"`
+	isLast      bool   `bits:"15"`
+	deltaShift  int8   `bits:"0-3"`
+	deltaScale  int16  `bits:"4-7"`
+}
+
+// Used to test packed arrays, with
+// element size only known at runtime
+type WithBitStream struct {
+	entryFormat uint8
+	mapCount    uint16
+	entries     []uint32 `arrayCount:"ComputedField-mapCount" bitWidth:"8 * (((entryFormat >> 4) & 3) + 1)"`
+	pointCount  uint8
+	points      []uint16 `arrayCount:"ComputedField-pointCount" bitWidth:"entryFormat&0xF + 1"`
+}
+
+// Used to test variable-length numbers
+type WithVarInts struct {
+	flags          uint8
+	origLength     uint32 `encoding:"UIntBase128"`
+	transformed    uint32 `encoding:"UIntBase128"`
+	numberOfPoints uint16
+	endPoints      []uint16    `arrayCount:"ComputedField-numberOfPoints" encoding:"255UInt16"`
+	glyphIDs       []uint32    `arrayCount:"First255UInt16"`
+	tables         []WithAlias `arrayCount:"FirstUIntBase128"`
+	charset        int32       `encoding:"CFFOperand"`
+	italicAngle    float64     `encoding:"CFFOperand"`
+}
+
+// Used to test compressed data
+type WithCompression struct {
+	origLength uint32
+	data       []byte `compression:"zlib" uncompressedLength:"origLength"`
+	docLength  uint32
+	doc        []byte `offsetSize:"Offset32" compression:"gzip" uncompressedLength:"docLength"`
+	arrayLen   uint16
+	array      WithArray `compression:"deflate" uncompressedLength:"arrayLen"`
+}
+
+// Used to test checksums
+type WithChecksums struct {
+	tableTag uint32
+	checkSum uint32 `checksum:"OpenType" checksumRange:"offset,length"`
+	offset   uint32
+	length   uint32
+	crc      uint32 `checksum:"CRC32"`
+	adler    uint32 `checksum:"Adler32" checksumRange:"offset, length/2"`
+}
+
+// Used to test checksums of the whole table, with unaligned fields
+type WithTableChecksums struct {
+	version uint16
+	sum     uint32 `checksum:"OpenType"`
+	adler   uint32 `checksum:"Adler32"`
+	data    []byte `arrayCount:"ToEnd"`
+}
+
+// Used to test reserved fields and alignment
+// binarygen: strict
+type WithReserved struct {
+	version    uint16
+	_          uint16
+	flags      uint8
+	glyphCount uint16  `align:"4"`
+	pad        float32 `reserved:"2"`
+	glyphs     []byte  `arrayCount:"ComputedField-glyphCount"`
+	tag        uint32  `align:"4"`
+	_          [3]byte
+	last       uint8
+}
+
+// Used to test reserved fields in a fixed size struct
+type WithFixedReserved struct {
+	format uint16
+	_      [2]uint16
+	_      uint8 `bits:"0-3"`
+	level  uint8 `bits:"4-7"`
+	value  int16 `align:"2"`
+}
+
+// Used to test the checks of nested strict structs
+type WithStrictElements struct {
+	single  strictRecord
+	pair    [2]strictRecord
+	records []strictRecord `arrayCount:"FirstUint8"`
+}
+
+// binarygen: strict
+type strictRecord struct {
+	value uint16
+	_     uint16
+}
+
+// Used to test constant values
+type WithExpect struct {
+	majorVersion uint16            `expect:"1"`
+	minorVersion uint16            `expect:"0|1"`
+	magicNumber  uint32            `expect:"0x5F0F3CF5"`
+	glyphs       []uint16          `arrayCount:"FirstUint16"`
+	offSize      int8              `expect:"-1|1|2"`
+	records      []WithFixedExpect `arrayCount:"FirstUint8"`
+}
+
+// Used to test constant values in nested structs
+type WithFixedExpect struct {
+	version uint16 `expect:"2"`
+	value   int16
+}
+
+// Used to test declarative constraints
+// binarygen: argument=numGlyphs int
+type WithConstraints struct {
+	format       uint16   `oneOf:"1|2|4"`
+	unitsPerEm   uint16   `min:"16" max:"16384"`
+	count        uint16   `nonZero:""`
+	defaultGlyph uint16   `lessThan:"numGlyphs"`
+	glyphs       []uint16 `arrayCount:"ComputedField-count" lessThan:"numGlyphs"`
+	classes      [2]int8  `min:"-1" max:"1"`
+}
+
+// Used to test the constraints of nested structs
+// binarygen: argument=numGlyphs int
+type WithConstrainedRecords struct {
+	records []constrainedRecord `arrayCount:"FirstUint16"`
+	first   constrainedRecord
+	pair    [2]constrainedRecord
+	nested  WithConstraints `offsetSize:"Offset16"`
+}
+
+// binarygen: argument=numGlyphs int
+type constrainedRecord struct {
+	glyph uint16 `lessThan:"numGlyphs"`
+	class uint16 `max:"3"`
+}
+
+// Used to test sorted records
+type WithSortedRecords struct {
+	glyphs []glyphRecord `arrayCount:"FirstUint16" sortedBy:"glyph" checkSorted:""`
+	ranges []rangeRecord `arrayCount:"FirstUint16" sortedBy:"start,end"`
+	kinds  []kindRecord  `arrayCount:"FirstUint16" sortedBy:"start" checkSorted:""`
+}
+
+// Used to test sorted records with checks
+type kindRecord struct {
+	start uint16
+	kind  extendMode `isEnum:""`
+}
+
+type glyphRecord struct {
+	glyph uint16
+	class uint16
+}
+
+type rangeRecord struct {
+	start      uint16
+	end        uint16
+	startIndex uint16
+}
+
+// Used to test maps decoded from records
+type WithMaps struct {
+	tables  map[Tag]tableRecord `arrayCount:"FirstUint16" mapKey:"tag"`
+	version uint16
+	scripts map[Tag]scriptRecord        `arrayCount:"FirstUint16" mapKey:"tag"`
+	axes    map[opentype.Tag]axisRecord `arrayCount:"FirstUint8" mapKey:"tag"`
+	classes map[uint16]classDef         `arrayCount:"FirstUint16" offsetsArray:"Offset16" mapKey:"format"`
+}
+
+type classDef struct {
+	format uint16
+	start  uint16
+	values []uint16 `arrayCount:"FirstUint16"`
+}
+
+type axisRecord struct {
+	tag          opentype.Tag
+	defaultValue opentype.Fixed
+}
+
+type Tag uint32
+
+type tableRecord struct {
+	tag      Tag
+	checksum uint32
+	offset   uint32
+	length   uint32
+}
+
+type scriptRecord struct {
+	tag    Tag
+	script WithArray `offsetSize:"Offset16" offsetRelativeTo:"Parent"`
+}
+
+// Used to test strings
+type WithStrings struct {
+	version    uint16
+	pascal     string `stringLayout:"Pascal" encoding:"MacRoman"`
+	fixed      string `stringLayout:"Fixed-8" encoding:"ASCII"`
+	wide       string `stringLayout:"Fixed-16" encoding:"UTF-16BE"`
+	length     uint16
+	offset     uint16
+	named      name `offsetSize:"Offset16" arrayCount:"ComputedField-length" encoding:"UTF-16BE"`
+	nameLength uint16
+	utf8       string `arrayCount:"ComputedField-nameLength"`
+	counted    string `arrayCount:"FirstUint16" encoding:"ASCII"`
+}
+
+type name string
+
+// Used to test elements whose size is only known at runtime,
+// with a width field similar to the offSize of a CFF INDEX
+type WithByteWidth struct {
+	count   uint16
+	offSize uint8
+	offsets []uint32 `arrayCount:"ComputedField-count" byteWidth:"offSize"`
+	format  uint8
+	small   []uint16    `arrayCount:"FirstUint16" byteWidth:"format&1 + 1"`
+	tables  []WithArray `arrayCount:"ComputedField-count" offsetsArray:"Offset32" byteWidth:"offSize"`
+}
+
+// Used to test a CFF INDEX : count+1 offsets (none when the INDEX is empty,
+// which then has no offSize), 1-based and relative to the byte preceding the data
+type CFFIndex struct {
+	count   uint16
+	offSize uint8    `presentIf:"count"`
+	offsets []uint32 `arrayCount:"ComputedField-offsetsCount()" byteWidth:"offSize"`
+	data    []byte   `arrayCount:"ComputedField-dataLength()"`
+}
+
+func (ci *CFFIndex) offsetsCount() int {
+	if ci.count == 0 {
+		return 0
+	}
+	return int(ci.count) + 1
+}
+
+// dataLength requires valid offsets, checked by [afterOffsets]
+func (ci *CFFIndex) dataLength() int {
+	if len(ci.offsets) == 0 {
+		return 0
+	}
+	return int(ci.offsets[len(ci.offsets)-1]) - 1
+}
+
+func (ci *CFFIndex) afterOffsets() error {
+	for i, offset := range ci.offsets {
+		if i == 0 && offset != 1 || i != 0 && offset < ci.offsets[i-1] {
+			return errors.New("invalid CFF INDEX offsets")
+		}
+	}
+	return nil
+}
+
+// item returns the i-th object of the INDEX
+func (ci *CFFIndex) item(i int) []byte { return ci.data[ci.offsets[i]-1 : ci.offsets[i+1]-1] }
+
+// Used to test 24-bit integers and offsets,
+// as in COLR version 1 and cmap format 14
+type WithUint24 struct {
+	varSelector uint32      `binarySize:"3"`
+	glyphs      []uint32    `arrayCount:"FirstUint24" binarySize:"3"`
+	delta       int         `binarySize:"2"`
+	layers      []byte      `arrayCount:"FirstUint8"`
+	paint       WithArray   `offsetSize:"Offset24"`
+	clips       []WithArray `arrayCount:"FirstUint16" offsetsArray:"Offset24"`
+	ranges      [2]uint32   `binarySize:"3"`
+}
+
+// Used to test storage independent of the Go type
+type WithBinarySize struct {
+	count  int       `binarySize:"uint16"`
+	delta  int32     `binarySize:"int16"`
+	offset int       `binarySize:"int24"`
+	small  int16     `binarySize:"int8"`
+	deltas []int     `arrayCount:"ComputedField-count" binarySize:"int16"`
+	size   uintptr   `binarySize:"uint32"`
+	values [2]uint64 `binarySize:"uint24"`
+}
+
+type angle float32
+
+// Used to test IEEE and fixed point floats
+type WithFloats struct {
+	single  float32
+	double  float64
+	named   angle
+	scale   float32    `encoding:"F2Dot14"`
+	advance float64    `encoding:"Fixed"`
+	matrix  [4]float32 `encoding:"F2Dot14"`
+	deltas  []angle    `arrayCount:"FirstUint16" encoding:"Fixed"`
+}
+
+type paintFormat uint8
+
+const (
+	paintSolid  paintFormat = 2
+	paintLinear paintFormat = 4
+	paintRadial paintFormat = 6
+	paintGlyph  paintFormat = 10
+)
+
+type extendMode uint8
+
+const (
+	extendPad extendMode = iota
+	extendRepeat
+	extendReflect
+)
+
+func (ex extendMode) String() string { return "custom" }
+
+type visible bool
+
+// Used to test bools and enums
+// binarygen: strict
+type WithEnums struct {
+	format  paintFormat `isEnum:""`
+	hidden  bool
+	shown   visible
+	extend  extendMode          `isEnum:""`
+	version subtableFlagVersion `isEnum:""`
+}
+
+// Used to test enums and strict bools in arrays,
+// slices and nested structs
+// binarygen: strict
+type WithEnumElements struct {
+	formats [2]paintFormat `isEnum:""`
+	flags   [2]bool
+	modes   []extendMode `arrayCount:"FirstUint8" isEnum:""`
+	states  []bool       `arrayCount:"FirstUint8"`
+	record  enumRecord
+	pair    [2]enumRecord
+}
+
+// binarygen: strict
+type enumRecord struct {
+	format paintFormat `isEnum:""`
+	hidden bool
+}
+
+// Used to test bools outside of strict mode
+type WithBools struct {
+	a, b bool
+	c    [2]bool
+	d    []bool `arrayCount:"FirstUint8"`
+}
+
+// Used to test the types of the opentype package
+type WithScalars struct {
+	version  opentype.Version16Dot16
+	created  opentype.LongDateTime
+	tag      opentype.Tag
+	scale    opentype.F2Dot14
+	advance  opentype.Fixed
+	glyphs   []opentype.GlyphID `arrayCount:"FirstUint16"`
+	paint    opentype.Offset24
+	subtable opentype.Offset16
+}
+
+// Used to test custom constructors
+type WithConstructors struct {
+	scale    float214
+	class    markClass
+	name     nameID
+	readOnly readOnly
+	classes  []markClass `arrayCount:"FirstUint16"`
+	pair     [2]markClass
+	record   classRecord
+	records  []classRecord `arrayCount:"FirstUint8"`
+}
+
+// Used to test fallible constructors in nested structs
+type classRecord struct {
+	class markClass
+	name  nameID
+}
+
+// Used to test codecs
+type WithCodecs struct {
+	created  time.Time
+	modified time.Time
+	dates    []time.Time `arrayCount:"FirstUint16"`
+	value    big.Int
+	values   []big.Int `arrayCount:"FirstUint8"`
+	largest  big.Int   `offsetSize:"Offset16"`
+	period   [2]time.Time
+	record   dateRecord
+	records  []dateRecord `arrayCount:"FirstUint8" sortedBy:"count"`
+}
+
+// Used to test codecs in nested structs
+type dateRecord struct {
+	date  time.Time
+	count uint16
+}
+
+// Used to test optional fields, as found in GPOS ValueRecords
+// binarygen: argument=valueFormat uint16
+type valueRecord struct {
+	xPlacement int16       `presentIf:"valueFormat&0x0001"`
+	yPlacement int16       `presentIf:"valueFormat&0x0002"`
+	xAdvance   int16       `presentIf:"valueFormat&0x0004"`
+	yAdvance   int16       `presentIf:"valueFormat&0x0008"`
+	xPlaDevice DeviceTable `offsetSize:"Offset16" offsetRelativeTo:"Parent" presentIf:"valueFormat&0x0010"`
+}
+
+type WithOptionalFields struct {
+	valueFormat uint16
+	count       uint16
+	records     []valueRecord `arrayCount:"ComputedField-count" arguments:"valueFormat=.valueFormat"`
+	single      valueRecord   `arguments:"valueFormat=.valueFormat"`
+	flags       uint8
+	extra       uint32 `presentIf:"flags&1"`
+	last        uint16
+}
+
+// Used to test slices whose elements have a size only known at runtime,
+// but the same for all elements
+// binarygen: argument=width uint8
+type uniformRecord struct {
+	glyph   uint16
+	v       varSize  `offsetSize:"Offset16" offsetRelativeTo:"Parent"`
+	indices []uint32 `byteWidth:"width"`
+	bits    []uint8  `bitWidth:"width"`
+	data    []byte
+	value   valueRecord
+}
+
+type WithUniformSize struct {
+	valueFormat uint16
+	width       uint8
+	count       uint8
+	records     []uniformRecord `arrayCount:"FirstUint16" arguments:"width=.width, indicesCount=.count, bitsCount=.count, dataCount=.count, valueFormat=.valueFormat"`
+}
+
+// Used to test wrapper subtables, as GSUB/GPOS Extension lookups
+type WithExtension struct {
+	kind        lookupVersion
+	isExtension bool
+	subtable    lookupSubtable `unionField:"kind" unwrapFlag:"isExtension"`
+}
+
+type lookupVersion uint16
+
+const (
+	lookupVersion1         lookupVersion = 1
+	lookupVersion2         lookupVersion = 2
+	lookupVersionExtension lookupVersion = 7
+)
+
+type lookupSubtable interface {
+	isLookupSubtable()
+}
+
+func (lookupSubtable1) isLookupSubtable() {}
+func (lookupSubtable2) isLookupSubtable() {}
+
+type lookupSubtable1 struct {
+	format uint16
+	delta  int16
+}
+
+type lookupSubtable2 struct {
+	values []uint16 `arrayCount:"FirstUint16"`
+}
+
+// binarygen: unwrap
+type lookupSubtableExtension struct {
+	format uint16 `expect:"1"`
+	kind   lookupVersion
+	inner  lookupSubtable `offsetSize:"Offset32" unionField:"kind"`
+}
+
+// Used to test fields which are not part of the binary layout
+type WithSkipped struct {
+	glyphs  []uint16       `arrayCount:"FirstUint16"`
+	index   map[uint16]int `binary:"-"`
+	load    func() error   `binary:"-"` // not supported by the analysis
+	version uint16
+}
+
+type WithFixedSkipped struct {
+	a     uint16
+	cache []string `binary:"-"`
+	b     uint32
+}
+
+// Used to test parsing hooks
+// binarygen: argument=numGlyphs int
+type WithHooks struct {
+	count   uint16
+	glyphs  []uint16 `arrayCount:"ComputedField-count"`
+	flags   uint8
+	extra   uint16   `presentIf:"flags&1"`
+	pairs   []uint16 `arrayCount:"ComputedField-half"`
+	fixed   withFixedHooks
+	half    int `binary:"-"` // set by afterCount
+	srcSize int `binary:"-"` // set by parseStart
+}
+
+func (wh *WithHooks) parseStart(src []byte) { wh.srcSize = len(src) }
+
+func (wh *WithHooks) afterCount(numGlyphs int) error {
+	if int(wh.count) > numGlyphs {
+		return errors.New("too many glyphs")
+	}
+	wh.half = int(wh.count) / 2
+	return nil
+}
+
+func (wh *WithHooks) afterExtra() { wh.extra++ }
+
+func (wh WithHooks) validate() error {
+	if len(wh.pairs) != wh.half {
+		return errors.New("invalid pairs")
+	}
+	return nil
+}
+
+// Used to test arrays of elements with hooks
+type WithHookedArray struct {
+	pair [2]hookedElement
+}
+
+type hookedElement struct {
+	a uint16
+}
+
+func (he *hookedElement) afterA() error {
+	if he.a == 0 {
+		return errors.New("null value")
+	}
+	return nil
+}
+
+// binarygen: argument=numGlyphs int
+type withFixedHooks struct {
+	a, b uint16
+}
+
+func (wf *withFixedHooks) afterB(numGlyphs int) error {
+	if int(wf.b) >= numGlyphs {
+		return errors.New("invalid glyph")
+	}
+	return nil
+}
+
+func (wf *withFixedHooks) validate(numGlyphs int) error {
+	if wf.a > wf.b {
+		return errors.New("invalid range")
+	}
+	return nil
+}